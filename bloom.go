@@ -0,0 +1,99 @@
+package quaternary
+
+import "math"
+
+// Bloom is a bloom filter used to detect keys which were never inserted.
+// The first byte holds the number of bloom functions, the rest are the bloom bits.
+type Bloom []byte
+
+// makeBloom allocates a Bloom sized for n keys, so that about 2^-funcs of absent keys pass
+func makeBloom(n int, funcs byte) Bloom {
+	bits := int(math.Ceil(float64(n) * float64(funcs) / math.Ln2))
+	b := make(Bloom, 1+(bits+7)/8)
+	b[0] = funcs
+	return b
+}
+
+// bloomKey reduces a numeric key to the pair of words the bloom functions are derived from
+func bloomKey(num uint64) (a, b uint32) {
+	x := uint32(num)
+	high := uint32(num >> 32)
+	a = hash(hash(x, 0x9e3779b9, 0xffffffff)^high, 0x85ebca6b, 0xffffffff)
+	b = hash(hash(high, 0xc2b2ae35, 0xffffffff)^x, a, 0xffffffff) | 1
+	return
+}
+
+// bloomKeyBytes reduces a byte key to the pair of words the bloom functions are derived from
+func bloomKeyBytes(data []byte) (a, b uint32) {
+	return bloomKey(uint64(dataHash(ROUNDS+1, data))<<32 | uint64(dataHash(ROUNDS, data)))
+}
+
+func (f Bloom) put(a, b uint32) {
+	if len(f) <= 1 {
+		return
+	}
+	bits := uint64(len(f)-1) * 8
+	for i := uint32(0); i < uint32(f[0]); i++ {
+		idx := hash64(a+i*b, i, bits)
+		f[1+idx>>3] |= 1 << (idx & 7)
+	}
+}
+
+// get reports whether the key may have been inserted. A Bloom without functions passes
+// every key, a missing or corrupt Bloom passes none.
+func (f Bloom) get(a, b uint32) bool {
+	if len(f) == 0 {
+		return false
+	}
+	if f[0] == 0 {
+		// makeBloom allocates no bits without functions
+		return len(f) == 1
+	}
+	if len(f) == 1 {
+		return false
+	}
+	bits := uint64(len(f)-1) * 8
+	for i := uint32(0); i < uint32(f[0]); i++ {
+		idx := hash64(a+i*b, i, bits)
+		if (f[1+idx>>3]>>(idx&7))&1 == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// bloomKeyString reduces a string key to the pair of words the bloom functions are derived from
+func bloomKeyString(str string) (a, b uint32) {
+	if len(str) <= 7 {
+		return bloomKey(stringToUint64(str))
+	}
+	data := stringsToByte64(str)
+	return bloomKeyBytes(data[:])
+}
+
+// saltKey derives an independent pair of words for every salt, so that blooms with
+// different salts have independent false positives
+func saltKey(a, b, salt uint32) (uint32, uint32) {
+	a = hash(a^(salt*0x9e3779b9), 0x85ebca6b+salt, 0xffffffff)
+	b = hash(b^(salt*0xc2b2ae35), a, 0xffffffff) | 1
+	return a, b
+}
+
+func (f Bloom) putString(str string) {
+	f.put(bloomKeyString(str))
+}
+
+// GetUint64 checks if an uint64 value was inserted into the Bloom.
+func (f Bloom) GetUint64(num uint64) bool {
+	return f.get(bloomKey(num))
+}
+
+// GetBytes checks if a 64-byte array was inserted into the Bloom.
+func (f Bloom) GetBytes(data [64]byte) bool {
+	return f.get(bloomKeyBytes(data[:]))
+}
+
+// GetString checks if a string was inserted into the Bloom.
+func (f Bloom) GetString(str string) bool {
+	return f.get(bloomKeyString(str))
+}
@@ -0,0 +1,224 @@
+package quaternary
+
+import (
+	"container/heap"
+	"sort"
+)
+
+// Classifier maps keys to labels, prefix coding the labels so that frequent labels
+// use fewer bit planes. Plane j holds bit j of the codeword of every key whose codeword
+// is longer than j, lookups walk the planes until a codeword completes.
+//
+// The codebook is canonical: Labels are sorted by codeword length, then by value,
+// and Counts[l] is the number of labels with codeword length l.
+type Classifier struct {
+	Counts []int
+	Labels []uint64
+	Planes Planes
+}
+
+type huffmanNode struct {
+	weight int
+	label  uint64
+	leaf   bool
+	left   *huffmanNode
+	right  *huffmanNode
+}
+
+type huffmanHeap []*huffmanNode
+
+func (h huffmanHeap) Len() int { return len(h) }
+func (h huffmanHeap) Less(i, j int) bool {
+	if h[i].weight != h[j].weight {
+		return h[i].weight < h[j].weight
+	}
+	return h[i].label < h[j].label
+}
+func (h huffmanHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *huffmanHeap) Push(x interface{}) { *h = append(*h, x.(*huffmanNode)) }
+func (h *huffmanHeap) Pop() interface{} {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
+
+// huffmanLengths returns the Huffman codeword length of every label
+func huffmanLengths(freq map[uint64]int) map[uint64]int {
+	var h huffmanHeap
+	for label, weight := range freq {
+		h = append(h, &huffmanNode{weight: weight, label: label, leaf: true})
+	}
+	heap.Init(&h)
+	for h.Len() > 1 {
+		a := heap.Pop(&h).(*huffmanNode)
+		b := heap.Pop(&h).(*huffmanNode)
+		label := a.label
+		if b.label < label {
+			label = b.label
+		}
+		heap.Push(&h, &huffmanNode{weight: a.weight + b.weight, label: label, left: a, right: b})
+	}
+	var lengths = make(map[uint64]int, len(freq))
+	var walk func(n *huffmanNode, depth int)
+	walk = func(n *huffmanNode, depth int) {
+		if n.leaf {
+			lengths[n.label] = depth
+			return
+		}
+		walk(n.left, depth+1)
+		walk(n.right, depth+1)
+	}
+	if h.Len() == 1 {
+		walk(h[0], 0)
+	}
+	return lengths
+}
+
+// codebook builds the canonical codebook of the label frequencies, returning the codeword and its length per label
+func codebook(freq map[uint64]int) (counts []int, sorted []uint64, codes map[uint64]uint64, lengths map[uint64]int) {
+	for {
+		lengths = huffmanLengths(freq)
+		var longest int
+		for _, l := range lengths {
+			if l > longest {
+				longest = l
+			}
+		}
+		if longest <= 64 {
+			counts = make([]int, longest+1)
+			break
+		}
+		// flatten the distribution until the codewords fit 64 planes
+		for k := range freq {
+			freq[k] = freq[k]/2 + 1
+		}
+	}
+	for label, l := range lengths {
+		counts[l]++
+		sorted = append(sorted, label)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if lengths[sorted[i]] != lengths[sorted[j]] {
+			return lengths[sorted[i]] < lengths[sorted[j]]
+		}
+		return sorted[i] < sorted[j]
+	})
+	codes = make(map[uint64]uint64, len(sorted))
+	var code uint64
+	var prev int
+	for _, label := range sorted {
+		code <<= uint(lengths[label] - prev)
+		prev = lengths[label]
+		codes[label] = code
+		code++
+	}
+	return
+}
+
+func createClassifier[T Number](numbers map[T]uint64, data map[[64]byte]uint64) Classifier {
+	var freq = make(map[uint64]int)
+	for _, v := range numbers {
+		freq[v]++
+	}
+	for _, v := range data {
+		freq[v]++
+	}
+	counts, sorted, codes, lengths := codebook(freq)
+	c := Classifier{Counts: counts, Labels: sorted}
+	for j := 0; j+1 < len(counts); j++ {
+		var planeNumbers = make(map[T]bool)
+		for k, v := range numbers {
+			if l := lengths[v]; l > j {
+				planeNumbers[k] = (codes[v]>>uint(l-j-1))&1 == 1
+			}
+		}
+		var planeData = make(map[[64]byte]bool)
+		for k, v := range data {
+			if l := lengths[v]; l > j {
+				planeData[k] = (codes[v]>>uint(l-j-1))&1 == 1
+			}
+		}
+		c.Planes = append(c.Planes, createPlane(planeNumbers, planeData))
+	}
+	return c
+}
+
+// MakeClassifier creates a new Classifier from a map of numeric keys to labels.
+// The type T must satisfy the Number constraint.
+func MakeClassifier[T Number](numbers map[T]uint64) Classifier {
+	return createClassifier(numbers, make(map[[64]byte]uint64))
+}
+
+// MakeBytesClassifier creates a new Classifier from a map of 64-byte arrays to labels.
+func MakeBytesClassifier(data map[[64]byte]uint64) Classifier {
+	return createClassifier(make(map[int]uint64), data)
+}
+
+// MakeStringClassifier creates a new Classifier from a map of strings to labels.
+func MakeStringClassifier(string_map map[string]uint64) Classifier {
+	var data = make(map[[64]byte]uint64)
+	var nums = make(map[uint64]uint64)
+	for k, v := range string_map {
+		if len(k) <= 7 {
+			nums[stringToUint64(k)] = v
+		} else {
+			data[stringsToByte64(k)] = v
+		}
+	}
+	return createClassifier(nums, data)
+}
+
+// Size returns the byte size of the Classifier planes.
+func (c Classifier) Size() int {
+	return c.Planes.Size()
+}
+
+// decode walks the planes until a codeword completes
+func (c Classifier) decode(bit func(plane Filter) bool) uint64 {
+	if len(c.Labels) == 0 {
+		return 0
+	}
+	var code, first, index int
+	for l := 1; l < len(c.Counts); l++ {
+		if bit(Filter(c.Planes[l-1])) {
+			code |= 1
+		}
+		count := c.Counts[l]
+		if code-first < count {
+			return c.Labels[index+code-first]
+		}
+		index += count
+		first += count
+		first <<= 1
+		code <<= 1
+	}
+	return c.Labels[0]
+}
+
+// GetInt retrieves the label of an int key from the Classifier.
+func (c Classifier) GetInt(num int) uint64 {
+	return c.GetUint64(uint64(num))
+}
+
+// GetUint64 retrieves the label of an uint64 key from the Classifier.
+func (c Classifier) GetUint64(num uint64) uint64 {
+	return c.decode(func(plane Filter) bool {
+		return plane.GetUint64(num)
+	})
+}
+
+// GetBytes retrieves the label of a 64-byte array key from the Classifier.
+func (c Classifier) GetBytes(data [64]byte) uint64 {
+	return c.decode(func(plane Filter) bool {
+		return plane.GetBytes(data)
+	})
+}
+
+// GetString retrieves the label of a string key from the Classifier created by MakeStringClassifier.
+func (c Classifier) GetString(str string) uint64 {
+	if len(str) <= 7 {
+		return c.GetUint64(stringToUint64(str))
+	}
+	return c.GetBytes(stringsToByte64(str))
+}
@@ -0,0 +1,83 @@
+package quaternary
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestMakeClassifier(t *testing.T) {
+	const test = 10000
+
+	strs := skewedLabels(test)
+	c := MakeStringClassifier(strs)
+	for k, v := range strs {
+		if got := c.GetString(k); got != v {
+			t.Fatalf("GetString(%q) = %d want %d", k, got, v)
+		}
+	}
+	planes := MakeStringPlanes(8, strs).Size()
+	if c.Size() >= planes {
+		t.Fatalf("Classifier size %d not below Planes size %d", c.Size(), planes)
+	}
+	fmt.Printf("[Classifier] %d keys: %d bytes, planes %d bytes, filters %d bytes\n",
+		test, c.Size(), planes, 8*len(MakeStringMulti(8, strs)[0]))
+
+	var nums = make(map[int]uint64)
+	for i := 0; i < test; i++ {
+		nums[i] = uint64(i % 10 * i % 7)
+	}
+	cn := MakeClassifier(nums)
+	for k, v := range nums {
+		if got := cn.GetInt(k); got != v {
+			t.Fatalf("GetInt(%d) = %d want %d", k, got, v)
+		}
+	}
+}
+
+func TestClassifierCodebook(t *testing.T) {
+	// a single label needs no planes
+	c := MakeClassifier(map[int]uint64{1: 42, 2: 42})
+	if len(c.Planes) != 0 || c.GetInt(1) != 42 || c.GetInt(3) != 42 {
+		t.Fatalf("single label classifier: %d planes, label %d", len(c.Planes), c.GetInt(1))
+	}
+	if MakeClassifier(map[int]uint64{}).GetInt(1) != 0 {
+		t.Fatalf("empty classifier must return 0")
+	}
+
+	// fibonacci frequencies make the longest Huffman codewords
+	var freq = make(map[uint64]int)
+	a, b := 1, 1
+	for label := uint64(0); label < 80; label++ {
+		freq[label] = a
+		a, b = b, a+b
+	}
+	counts, sorted, codes, lengths := codebook(freq)
+	if len(counts) > 65 {
+		t.Fatalf("codewords %d planes long", len(counts)-1)
+	}
+	var seen = make(map[string]bool)
+	for _, label := range sorted {
+		code := fmt.Sprintf("%0*b", lengths[label], codes[label])
+		for other := range seen {
+			short, long := other, code
+			if len(short) > len(long) {
+				short, long = long, short
+			}
+			if long[:len(short)] == short {
+				t.Fatalf("codeword %s has prefix %s", long, short)
+			}
+		}
+		seen[code] = true
+	}
+
+	var bytes = make(map[[64]byte]uint64)
+	for i := 0; i < 1000; i++ {
+		bytes[stringsToByte64(fmt.Sprint("bytes", i))] = uint64(i % 3)
+	}
+	cb := MakeBytesClassifier(bytes)
+	for k, v := range bytes {
+		if got := cb.GetBytes(k); got != v {
+			t.Fatalf("GetBytes = %d want %d", got, v)
+		}
+	}
+}
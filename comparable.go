@@ -0,0 +1,440 @@
+package quaternary
+
+import (
+	"crypto/sha512"
+	"encoding/binary"
+	"math"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"unsafe"
+)
+
+// keyEncoder appends the deterministic encoding of a value of a single type
+type keyEncoder func(dst []byte, v reflect.Value) []byte
+
+// keyEncoders caches the keyEncoder of every type seen by MakeComparable and GetComparable
+var keyEncoders sync.Map
+
+// keyField is a scalar of a composite key, at offset in the memory of the key
+type keyField struct {
+	offset uintptr
+	kind   reflect.Kind
+}
+
+// keyLayout lists the scalars of a composite key type in the order its keyEncoder encodes them
+type keyLayout struct {
+	typ    reflect.Type
+	fields []keyField
+	// reflected is set for types holding interfaces, which only their keyEncoder can encode
+	reflected bool
+	// scalar is set for named numeric, bool and string types, stored like their underlying types
+	scalar bool
+}
+
+// keyLayouts caches the keyLayout of every composite key type seen by GetComparable
+var keyLayouts sync.Map
+
+// lastLayout holds the *keyLayout used last, sparing the keyLayouts lookup for the usual single key type
+var lastLayout atomic.Value
+
+// canonicalFloat returns the bits of f with -0 folded onto 0, as the two compare equal
+func canonicalFloat(f float64) uint64 {
+	if f == 0 {
+		return 0
+	}
+	return math.Float64bits(f)
+}
+
+// encoderOf returns the keyEncoder of type t, building and caching it on first use
+func encoderOf(t reflect.Type) keyEncoder {
+	if enc, ok := keyEncoders.Load(t); ok {
+		return enc.(keyEncoder)
+	}
+	enc := newEncoder(t)
+	keyEncoders.Store(t, enc)
+	return enc
+}
+
+func newEncoder(t reflect.Type) keyEncoder {
+	switch t.Kind() {
+	case reflect.Bool:
+		return func(dst []byte, v reflect.Value) []byte {
+			if v.Bool() {
+				return append(dst, 1)
+			}
+			return append(dst, 0)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return func(dst []byte, v reflect.Value) []byte {
+			var b [8]byte
+			binary.LittleEndian.PutUint64(b[:], uint64(v.Int()))
+			return append(dst, b[:]...)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return func(dst []byte, v reflect.Value) []byte {
+			var b [8]byte
+			binary.LittleEndian.PutUint64(b[:], v.Uint())
+			return append(dst, b[:]...)
+		}
+	case reflect.Float32, reflect.Float64:
+		return func(dst []byte, v reflect.Value) []byte {
+			var b [8]byte
+			binary.LittleEndian.PutUint64(b[:], canonicalFloat(v.Float()))
+			return append(dst, b[:]...)
+		}
+	case reflect.Complex64, reflect.Complex128:
+		return func(dst []byte, v reflect.Value) []byte {
+			var b [16]byte
+			binary.LittleEndian.PutUint64(b[:], canonicalFloat(real(v.Complex())))
+			binary.LittleEndian.PutUint64(b[8:], canonicalFloat(imag(v.Complex())))
+			return append(dst, b[:]...)
+		}
+	case reflect.String:
+		return func(dst []byte, v reflect.Value) []byte {
+			var n [binary.MaxVarintLen64]byte
+			dst = append(dst, n[:binary.PutUvarint(n[:], uint64(v.Len()))]...)
+			return append(dst, v.String()...)
+		}
+	case reflect.Pointer, reflect.Chan, reflect.UnsafePointer:
+		// MakeComparable rejects pointers, looking them up encodes their identity
+		return func(dst []byte, v reflect.Value) []byte {
+			var b [8]byte
+			binary.LittleEndian.PutUint64(b[:], uint64(v.Pointer()))
+			return append(dst, b[:]...)
+		}
+	case reflect.Array:
+		elem := encoderOf(t.Elem())
+		return func(dst []byte, v reflect.Value) []byte {
+			for i := 0; i < v.Len(); i++ {
+				dst = elem(dst, v.Index(i))
+			}
+			return dst
+		}
+	case reflect.Struct:
+		var fields []int
+		var encoders []keyEncoder
+		for i := 0; i < t.NumField(); i++ {
+			// blank fields are ignored by ==
+			if t.Field(i).Name == "_" {
+				continue
+			}
+			fields = append(fields, i)
+			encoders = append(encoders, encoderOf(t.Field(i).Type))
+		}
+		return func(dst []byte, v reflect.Value) []byte {
+			for i, field := range fields {
+				dst = encoders[i](dst, v.Field(field))
+			}
+			return dst
+		}
+	case reflect.Interface:
+		return func(dst []byte, v reflect.Value) []byte {
+			if v.IsNil() {
+				return append(dst, 0)
+			}
+			e := v.Elem()
+			name := e.Type().PkgPath() + "." + e.Type().String()
+			var n [binary.MaxVarintLen64]byte
+			dst = append(dst, n[:binary.PutUvarint(n[:], uint64(len(name)+1))]...)
+			dst = append(dst, name...)
+			return encoderOf(e.Type())(dst, e)
+		}
+	}
+	panic("type " + t.String() + " is not comparable")
+}
+
+// comparableKey converts key to the numeric key it is stored under, or sets data to its 64-byte key
+func comparableKey[K comparable](key K, data *[64]byte) (num uint64, isNum bool) {
+	switch k := any(&key).(type) {
+	case *int:
+		return uint64(*k), true
+	case *uint:
+		return uint64(*k), true
+	case *int8:
+		return uint64(*k), true
+	case *uint8:
+		return uint64(*k), true
+	case *int16:
+		return uint64(*k), true
+	case *uint16:
+		return uint64(*k), true
+	case *int32:
+		return uint64(*k), true
+	case *uint32:
+		return uint64(*k), true
+	case *int64:
+		return uint64(*k), true
+	case *uint64:
+		return uint64(*k), true
+	case *float32:
+		return canonicalFloat(float64(*k)), true
+	case *float64:
+		return canonicalFloat(*k), true
+	case *bool:
+		if *k {
+			return 1, true
+		}
+		return 0, true
+	case *string:
+		if len(*k) <= 7 {
+			return stringToUint64(*k), true
+		}
+		*data = stringsToByte64(*k)
+		return 0, false
+	}
+	return encodedKey(key, data)
+}
+
+// layoutOf returns the keyLayout of type t, building and caching it on first use
+func layoutOf(t reflect.Type) *keyLayout {
+	if l, ok := lastLayout.Load().(*keyLayout); ok && l.typ == t {
+		return l
+	}
+	if l, ok := keyLayouts.Load(t); ok {
+		lastLayout.Store(l)
+		return l.(*keyLayout)
+	}
+	l := &keyLayout{typ: t}
+	l.reflected = !l.add(t, 0)
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64, reflect.Bool, reflect.String:
+		l.scalar = true
+	}
+	keyLayouts.Store(t, l)
+	lastLayout.Store(l)
+	return l
+}
+
+// add appends the scalars of type t at offset, it returns false if t holds an interface
+func (l *keyLayout) add(t reflect.Type, offset uintptr) bool {
+	switch t.Kind() {
+	case reflect.Array:
+		for i := 0; i < t.Len(); i++ {
+			if !l.add(t.Elem(), offset+uintptr(i)*t.Elem().Size()) {
+				return false
+			}
+		}
+		return true
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			// blank fields are ignored by ==
+			if t.Field(i).Name == "_" {
+				continue
+			}
+			if !l.add(t.Field(i).Type, offset+t.Field(i).Offset) {
+				return false
+			}
+		}
+		return true
+	case reflect.Interface:
+		return false
+	}
+	l.fields = append(l.fields, keyField{offset, t.Kind()})
+	return true
+}
+
+// appendKey appends the encoding of the key at p, the same as the keyEncoder of its type
+func (l *keyLayout) appendKey(dst []byte, p unsafe.Pointer) []byte {
+	var b [16]byte
+	for _, f := range l.fields {
+		q := unsafe.Add(p, f.offset)
+		switch f.kind {
+		case reflect.Bool:
+			if *(*bool)(q) {
+				dst = append(dst, 1)
+			} else {
+				dst = append(dst, 0)
+			}
+			continue
+		case reflect.Int:
+			binary.LittleEndian.PutUint64(b[:], uint64(*(*int)(q)))
+		case reflect.Int8:
+			binary.LittleEndian.PutUint64(b[:], uint64(*(*int8)(q)))
+		case reflect.Int16:
+			binary.LittleEndian.PutUint64(b[:], uint64(*(*int16)(q)))
+		case reflect.Int32:
+			binary.LittleEndian.PutUint64(b[:], uint64(*(*int32)(q)))
+		case reflect.Int64:
+			binary.LittleEndian.PutUint64(b[:], uint64(*(*int64)(q)))
+		case reflect.Uint:
+			binary.LittleEndian.PutUint64(b[:], uint64(*(*uint)(q)))
+		case reflect.Uint8:
+			binary.LittleEndian.PutUint64(b[:], uint64(*(*uint8)(q)))
+		case reflect.Uint16:
+			binary.LittleEndian.PutUint64(b[:], uint64(*(*uint16)(q)))
+		case reflect.Uint32:
+			binary.LittleEndian.PutUint64(b[:], uint64(*(*uint32)(q)))
+		case reflect.Uint64:
+			binary.LittleEndian.PutUint64(b[:], *(*uint64)(q))
+		case reflect.Uintptr:
+			binary.LittleEndian.PutUint64(b[:], uint64(*(*uintptr)(q)))
+		case reflect.Float32:
+			binary.LittleEndian.PutUint64(b[:], canonicalFloat(float64(*(*float32)(q))))
+		case reflect.Float64:
+			binary.LittleEndian.PutUint64(b[:], canonicalFloat(*(*float64)(q)))
+		case reflect.Complex64:
+			c := *(*complex64)(q)
+			binary.LittleEndian.PutUint64(b[:], canonicalFloat(float64(real(c))))
+			binary.LittleEndian.PutUint64(b[8:], canonicalFloat(float64(imag(c))))
+			dst = append(dst, b[:16]...)
+			continue
+		case reflect.Complex128:
+			c := *(*complex128)(q)
+			binary.LittleEndian.PutUint64(b[:], canonicalFloat(real(c)))
+			binary.LittleEndian.PutUint64(b[8:], canonicalFloat(imag(c)))
+			dst = append(dst, b[:16]...)
+			continue
+		case reflect.String:
+			str := *(*string)(q)
+			dst = append(dst, b[:binary.PutUvarint(b[:], uint64(len(str)))]...)
+			dst = append(dst, str...)
+			continue
+		case reflect.Pointer, reflect.Chan, reflect.UnsafePointer:
+			// MakeComparable rejects pointers, looking them up encodes their identity
+			binary.LittleEndian.PutUint64(b[:], uint64(uintptr(*(*unsafe.Pointer)(q))))
+		default:
+			panic("type " + f.kind.String() + " is not comparable")
+		}
+		dst = append(dst, b[:8]...)
+	}
+	return dst
+}
+
+// scalarKey converts the key at p of a named scalar type like comparableKey converts its underlying type
+func scalarKey(kind reflect.Kind, p unsafe.Pointer, data *[64]byte) (num uint64, isNum bool) {
+	switch kind {
+	case reflect.Int:
+		return uint64(*(*int)(p)), true
+	case reflect.Int8:
+		return uint64(*(*int8)(p)), true
+	case reflect.Int16:
+		return uint64(*(*int16)(p)), true
+	case reflect.Int32:
+		return uint64(*(*int32)(p)), true
+	case reflect.Int64:
+		return uint64(*(*int64)(p)), true
+	case reflect.Uint:
+		return uint64(*(*uint)(p)), true
+	case reflect.Uint8:
+		return uint64(*(*uint8)(p)), true
+	case reflect.Uint16:
+		return uint64(*(*uint16)(p)), true
+	case reflect.Uint32:
+		return uint64(*(*uint32)(p)), true
+	case reflect.Uint64:
+		return *(*uint64)(p), true
+	case reflect.Float32:
+		return canonicalFloat(float64(*(*float32)(p))), true
+	case reflect.Float64:
+		return canonicalFloat(*(*float64)(p)), true
+	case reflect.Bool:
+		if *(*bool)(p) {
+			return 1, true
+		}
+		return 0, true
+	case reflect.String:
+		str := *(*string)(p)
+		if len(str) <= 7 {
+			return stringToUint64(str), true
+		}
+		*data = stringsToByte64(str)
+		return 0, false
+	}
+	panic("type " + kind.String() + " is not a scalar")
+}
+
+// encodedKey converts key of a named scalar or a composite type by its keyLayout, without allocating
+func encodedKey[K comparable](key K, data *[64]byte) (num uint64, isNum bool) {
+	l := layoutOf(reflect.TypeOf((*K)(nil)).Elem())
+	if l.scalar {
+		return scalarKey(l.typ.Kind(), unsafe.Pointer(&key), data)
+	}
+	if l.reflected {
+		return reflectedKey(key, data)
+	}
+	var scratch [64]byte
+	return packedKey(l.appendKey(scratch[:0], unsafe.Pointer(&key)), data)
+}
+
+// reflectedKey converts key of a composite type holding interfaces using its keyEncoder
+func reflectedKey[K comparable](key K, data *[64]byte) (num uint64, isNum bool) {
+	v := reflect.ValueOf(&key).Elem()
+	return packedKey(encoderOf(v.Type())(nil, v), data)
+}
+
+// packedKey converts an encoded key to the numeric key, or sets data to the 64-byte key
+func packedKey(b []byte, data *[64]byte) (num uint64, isNum bool) {
+	if len(b) <= 7 {
+		return bytesToUint64(b), true
+	}
+	if len(b) <= 63 {
+		n := copy(data[:], b)
+		data[n] = byte(n)
+		return 0, false
+	}
+	*data = sha512.Sum512(b)
+	return 0, false
+}
+
+// bytesToUint64 packs up to 7 bytes and their length like stringToUint64
+func bytesToUint64(b []byte) uint64 {
+	var result uint64
+	for i := 0; i < len(b) && i < 8; i++ {
+		result |= uint64(b[i]) << (8 * i)
+	}
+	result |= uint64(len(b)) << 56
+	return result
+}
+
+// holdsPointer reports whether values of type t hold a pointer, chan or unsafe pointer
+func holdsPointer(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Chan, reflect.UnsafePointer:
+		return true
+	case reflect.Array:
+		return holdsPointer(t.Elem())
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if t.Field(i).Name != "_" && holdsPointer(t.Field(i).Type) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// MakeComparable creates a new Filter from a map of any comparable keys,
+// such as structs, arrays and floats. Keys are encoded deterministically field by field,
+// numeric keys, including named numeric types, are stored like Make and string keys like MakeString.
+// It panics for key types holding pointers, as their identity doesn't survive serialization,
+// the same as the v1 package rejects pointer keys.
+func MakeComparable[K comparable](m map[K]bool) Filter {
+	if holdsPointer(reflect.TypeOf((*K)(nil)).Elem()) {
+		panic("pointer keys are not supported, use the pointee")
+	}
+	var data = make(map[[64]byte]bool)
+	var nums = make(map[uint64]bool)
+	for k, v := range m {
+		var d [64]byte
+		num, isNum := comparableKey(k, &d)
+		if isNum {
+			nums[num] = v
+		} else {
+			data[d] = v
+		}
+	}
+	return create(nums, data, false)[0]
+}
+
+// GetComparable checks if a comparable key exists in the Filter created by MakeComparable.
+func GetComparable[K comparable](f Filter, key K) bool {
+	var data [64]byte
+	num, isNum := comparableKey(key, &data)
+	if isNum {
+		return f.GetUint64(num)
+	}
+	return f.GetBytes(data)
+}
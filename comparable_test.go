@@ -0,0 +1,200 @@
+package quaternary
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+	"unsafe"
+)
+
+type comparablePoint struct {
+	X, Y  float64
+	label string
+	_     int
+}
+
+type comparableAny struct {
+	value interface{}
+}
+
+func TestMakeComparable(t *testing.T) {
+	const test = 2000
+
+	var points = make(map[comparablePoint]bool)
+	var arrays = make(map[[3]int16]bool)
+	var floats = make(map[float64]bool)
+	for i := 0; i < test; i++ {
+		points[comparablePoint{X: float64(i) / 3, Y: -float64(i), label: fmt.Sprint(i % 7)}] = i%2 == 0
+		arrays[[3]int16{int16(i), int16(-i), 7}] = i%3 == 0
+		floats[float64(i)*0.25] = i%5 == 0
+	}
+
+	fp := MakeComparable(points)
+	for k, v := range points {
+		if got := GetComparable(fp, k); got != v {
+			t.Fatalf("GetComparable(%v) = %v want %v", k, got, v)
+		}
+	}
+	fa := MakeComparable(arrays)
+	for k, v := range arrays {
+		if got := GetComparable(fa, k); got != v {
+			t.Fatalf("GetComparable(%v) = %v want %v", k, got, v)
+		}
+	}
+	ff := MakeComparable(floats)
+	for k, v := range floats {
+		if got := GetComparable(ff, k); got != v {
+			t.Fatalf("GetComparable(%v) = %v want %v", k, got, v)
+		}
+	}
+}
+
+func TestComparableKeyEncoding(t *testing.T) {
+	var zero, negZero [64]byte
+	if a, _ := comparableKey(0.0, &zero); a != func() uint64 { n, _ := comparableKey(math.Copysign(0, -1), &negZero); return n }() {
+		t.Fatalf("-0 and 0 are different keys")
+	}
+	// unexported fields take part in the key
+	var pd, qd [64]byte
+	p, _ := comparableKey(comparablePoint{label: "a"}, &pd)
+	q, _ := comparableKey(comparablePoint{label: "b"}, &qd)
+	if p == q && pd == qd {
+		t.Fatalf("unexported field ignored")
+	}
+	// interface fields encode the dynamic type
+	enc := encoderOf(reflect.TypeOf(comparableAny{}))
+	encode := func(v interface{}) string {
+		return string(enc(nil, reflect.ValueOf(comparableAny{v})))
+	}
+	if encode(int(1)) == encode(uint(1)) || encode(nil) == encode(0) || encode("a") != encode("a") {
+		t.Fatalf("interface encoding ambiguous")
+	}
+	// numeric and string keys are stored like Make and MakeString
+	f := MakeComparable(map[int]bool{5: true, 55: false})
+	if !Filter(f).GetInt(5) || Filter(f).GetInt(55) {
+		t.Fatalf("numeric keys differ from Make")
+	}
+	s := MakeComparable(map[string]bool{"a": true, "long string key": false})
+	if !s.GetString("a") || s.GetString("long string key") {
+		t.Fatalf("string keys differ from MakeString")
+	}
+}
+
+type comparableID int
+
+type comparableName string
+
+func TestComparableNamedKeys(t *testing.T) {
+	// named numeric and string keys are stored like their underlying types
+	f := MakeComparable(map[comparableID]bool{5: true, -55: false})
+	if !f.GetInt(5) || f.GetInt(-55) || !GetComparable(f, comparableID(5)) {
+		t.Fatalf("named numeric keys differ from Make")
+	}
+	s := MakeComparable(map[comparableName]bool{"a": true, "long string key": false})
+	if !s.GetString("a") || s.GetString("long string key") || !GetComparable(s, comparableName("a")) {
+		t.Fatalf("named string keys differ from MakeString")
+	}
+	if n := testing.AllocsPerRun(100, func() { GetComparable(f, comparableID(5)) }); n != 0 {
+		t.Fatalf("GetComparable allocates %v times per lookup", n)
+	}
+}
+
+func TestComparablePointerKeys(t *testing.T) {
+	var x int
+	for name, build := range map[string]func(){
+		"pointer": func() { MakeComparable(map[*int]bool{&x: true}) },
+		"field":   func() { MakeComparable(map[comparableNested]bool{{p: &x}: true}) },
+		"chan":    func() { MakeComparable(map[[1]chan int]bool{{nil}: true}) },
+	} {
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Fatalf("%s: expected panic for a key holding a pointer", name)
+				}
+			}()
+			build()
+		}()
+	}
+	// lookups don't panic
+	GetComparable(MakeComparable(map[int]bool{1: true}), &x)
+}
+
+type comparableNested struct {
+	ok    bool
+	small int8
+	pair  [2]uint16
+	c     complex64
+	p     *int
+	point comparablePoint
+}
+
+func TestComparableLayout(t *testing.T) {
+	// the layout encodes like the keyEncoder of the type
+	var x int
+	keys := []comparableNested{
+		{},
+		{ok: true, small: -3, pair: [2]uint16{1, 65535}, c: complex(1, -2), p: &x},
+		{point: comparablePoint{X: math.Copysign(0, -1), Y: 2.5, label: "label"}},
+	}
+	for _, k := range keys {
+		want := encoderOf(reflect.TypeOf(k))(nil, reflect.ValueOf(k))
+		got := layoutOf(reflect.TypeOf(k)).appendKey(nil, unsafe.Pointer(&k))
+		if string(got) != string(want) {
+			t.Fatalf("layout of %v encodes %x want %x", k, got, want)
+		}
+	}
+	if !layoutOf(reflect.TypeOf(comparableAny{})).reflected {
+		t.Fatalf("interface fields must be encoded by reflection")
+	}
+}
+
+func TestGetComparableAllocs(t *testing.T) {
+	points := map[comparablePoint]bool{{X: 1, label: "p"}: true, {X: 2, label: "p"}: false}
+	f := MakeComparable(points)
+	key := comparablePoint{X: 1, label: "p"}
+	if n := testing.AllocsPerRun(100, func() { GetComparable(f, key) }); n != 0 {
+		t.Fatalf("GetComparable allocates %v times per lookup", n)
+	}
+	arrays := MakeComparable(map[[3]int16]bool{{1, 2, 3}: true})
+	if n := testing.AllocsPerRun(100, func() { GetComparable(arrays, [3]int16{1, 2, 3}) }); n != 0 {
+		t.Fatalf("GetComparable allocates %v times per lookup", n)
+	}
+}
+
+func BenchmarkGetComparable(b *testing.B) {
+	var points = make(map[comparablePoint]bool)
+	var ints = make(map[int]bool)
+	for i := 0; i < 10000; i++ {
+		points[comparablePoint{X: float64(i), label: "p"}] = i%2 == 0
+		ints[i] = i%2 == 0
+	}
+	fp := MakeComparable(points)
+	fi := MakeComparable(ints)
+	b.Run("struct", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			GetComparable(fp, comparablePoint{X: float64(i % 10000), label: "p"})
+		}
+	})
+	b.Run("GetBytes", func(b *testing.B) {
+		// the same keys as struct, encoded upfront
+		data := make([][64]byte, 10000)
+		for i := range data {
+			comparableKey(comparablePoint{X: float64(i), label: "p"}, &data[i])
+		}
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			fp.GetBytes(data[i%10000])
+		}
+	})
+	b.Run("int", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			GetComparable(fi, i%10000)
+		}
+	})
+	b.Run("GetInt", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			fi.GetInt(i % 10000)
+		}
+	})
+}
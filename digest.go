@@ -0,0 +1,120 @@
+package quaternary
+
+import "encoding/binary"
+
+// Digest is a key which already is a uniformly distributed hash, such as a SHA-256 sum
+// or a 128-bit content address. Digest keys are used directly, without hashing them again.
+type Digest interface {
+	[16]byte | [32]byte
+}
+
+// digestWords splits a digest key into the words the rounds are derived from
+func digestWords[D Digest](key D) (w [8]uint32) {
+	switch k := any(&key).(type) {
+	case *[32]byte:
+		for i := range w {
+			w[i] = binary.LittleEndian.Uint32(k[4*i:])
+		}
+	case *[16]byte:
+		for i := 0; i < 4; i++ {
+			w[i] = binary.LittleEndian.Uint32(k[4*i:])
+			w[i+4] = hash(w[i], w[(i+1)&3]^0x9e3779b9, 0xffffffff)
+		}
+	}
+	return
+}
+
+// digestRound is the round i hash of a digest key, pairing two different words each round
+func digestRound(w *[8]uint32, i uint32) uint32 {
+	return hash(w[i&7], w[(i+1+(i>>3)%7)&7]^i, 0xffffffff)
+}
+
+func (f Filter) storeDigest(w *[8]uint32, answer byte) (inserted int) {
+	if len(f) == 0 {
+		return 1
+	}
+	cells := cellSize(len(f))
+	for i := uint32(0); i < ROUNDS; i++ {
+		h := hash64(digestRound(w, i), uint32(cells), uint64(cells)<<1)
+		switch ((f)[h>>3] >> (h & 6)) & 3 {
+		case 0:
+			if answer == byte(h&1) {
+				return inserted
+			}
+			(f)[h>>3] |= ((answer & 1) + 1) << (h & 6)
+			inserted++
+			return inserted
+		case 1:
+			if answer == 0 {
+				return inserted
+			}
+		case 2:
+			if answer == 1 {
+				return inserted
+			}
+		default:
+			continue
+		}
+		(f)[h>>3] |= 3 << (h & 6)
+		inserted++
+	}
+	return inserted + 1
+}
+
+func (f Filter) getDigest(w *[8]uint32) bool {
+	if len(f) == 0 {
+		return false
+	}
+	cells := cellSize(len(f))
+	for i := uint32(0); i < ROUNDS; i++ {
+		h := hash64(digestRound(w, i), uint32(cells), uint64(cells)<<1)
+		switch ((f)[h>>3] >> (h & 6)) & 3 {
+		case 0:
+			return byte(h&1) == 1
+		case 1:
+			return false
+		case 2:
+			return true
+		case 3:
+			continue
+		}
+	}
+	return false
+}
+
+// MakeDigest creates a new Filter from a map keyed by digests.
+// Query it by GetDigest or GetDigest16 matching the digest size.
+func MakeDigest[D Digest](digests map[D]bool) Filter {
+	if len(digests) == 0 {
+		return nil
+	}
+	words := make(map[[8]uint32]bool, len(digests))
+	for k, v := range digests {
+		words[digestWords(k)] = v
+	}
+	return fill(byteSize(grow(len(words))), len(words), func(filter Filter, budget int) (new_inserted int) {
+		for k, v := range words {
+			if v {
+				new_inserted += filter.storeDigest(&k, 1)
+			} else {
+				new_inserted += filter.storeDigest(&k, 0)
+			}
+			if new_inserted >= budget {
+				break
+			}
+		}
+		return
+	})
+}
+
+// GetDigest checks if a 32-byte digest exists in the Filter created by MakeDigest.
+func (f Filter) GetDigest(digest [32]byte) bool {
+	w := digestWords(digest)
+	return f.getDigest(&w)
+}
+
+// GetDigest16 checks if a 16-byte digest exists in the Filter created by MakeDigest.
+func (f Filter) GetDigest16(digest [16]byte) bool {
+	w := digestWords(digest)
+	return f.getDigest(&w)
+}
@@ -0,0 +1,49 @@
+package quaternary
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"fmt"
+	"testing"
+)
+
+func TestDigest(t *testing.T) {
+	const test = 10000
+
+	var sums = make(map[[32]byte]bool)
+	var md5s = make(map[[16]byte]bool)
+	for i := 0; i < test; i++ {
+		sums[sha256.Sum256([]byte(fmt.Sprint("key", i)))] = i%3 == 0
+		md5s[md5.Sum([]byte(fmt.Sprint("key", i)))] = i%3 == 1
+	}
+	f := MakeDigest(sums)
+	for k, v := range sums {
+		if got := f.GetDigest(k); got != v {
+			t.Fatalf("GetDigest(%x) = %v want %v", k, got, v)
+		}
+	}
+	f16 := MakeDigest(md5s)
+	for k, v := range md5s {
+		if got := f16.GetDigest16(k); got != v {
+			t.Fatalf("GetDigest16(%x) = %v want %v", k, got, v)
+		}
+	}
+	if allocs := testing.AllocsPerRun(100, func() { f.GetDigest([32]byte{1}) }); allocs != 0 {
+		t.Fatalf("GetDigest allocates %v times", allocs)
+	}
+	if MakeDigest(map[[32]byte]bool{}).GetDigest([32]byte{}) {
+		t.Fatalf("empty filter returned true")
+	}
+}
+
+func TestDigestRoundWords(t *testing.T) {
+	// every round pairs a word with another one
+	var w [8]uint32
+	for i := uint32(0); i < ROUNDS; i++ {
+		w = [8]uint32{}
+		w[i&7] = 1
+		if digestRound(&w, i) == hash(1, 1^i, 0xffffffff) {
+			t.Fatalf("round %d pairs word %d with itself", i, i&7)
+		}
+	}
+}
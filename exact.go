@@ -0,0 +1,124 @@
+package quaternary
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// ExactSet is a cascade of Blooms answering membership exactly for every key of a known universe.
+// Level 0 holds the included keys, every next level holds the false positives of the previous
+// level among the keys of the other side. The layout is a 4 byte little endian Bloom length
+// followed by the Bloom, for every level.
+type ExactSet []byte
+
+// maxExactLevels bounds the cascade, keys colliding in all bloom functions never separate
+const maxExactLevels = 64
+
+// makeExactSet builds the cascade from the bloom key pairs of the included and the excluded keys
+func makeExactSet(included, excluded [][2]uint32) ExactSet {
+	var set ExactSet
+	var funcs byte = 1
+	if len(excluded) > len(included) && len(included) > 0 {
+		// the first level filters the larger excluded side, at rate r*sqrt(2)/s
+		rate := float64(len(included)) * math.Sqrt2 / float64(len(excluded))
+		funcs = byte(math.Max(1, math.Min(32, math.Round(-math.Log2(rate)))))
+	}
+	inside, outside := included, excluded
+	for level := uint32(0); len(inside) > 0; level++ {
+		if level == maxExactLevels {
+			panic("keys collide, they can't be told apart")
+		}
+		bloom := makeBloom(len(inside), funcs)
+		for _, k := range inside {
+			bloom.put(saltKey(k[0], k[1], level))
+		}
+		var passed [][2]uint32
+		for _, k := range outside {
+			if bloom.get(saltKey(k[0], k[1], level)) {
+				passed = append(passed, k)
+			}
+		}
+		var n [4]byte
+		binary.LittleEndian.PutUint32(n[:], uint32(len(bloom)))
+		set = append(set, n[:]...)
+		set = append(set, bloom...)
+		inside, outside = passed, inside
+		funcs = 1
+	}
+	return set
+}
+
+// contains walks the cascade, a key stopped at an odd level or passing an odd number of levels is included.
+// A level cut short by truncation includes nothing.
+func (s ExactSet) contains(a, b uint32) bool {
+	level := uint32(0)
+	for len(s) >= 4 {
+		n := uint64(binary.LittleEndian.Uint32(s))
+		if 4+n > uint64(len(s)) {
+			return false
+		}
+		if !Bloom(s[4 : 4+n]).get(saltKey(a, b, level)) {
+			return level%2 == 1
+		}
+		s = s[4+n:]
+		level++
+	}
+	return level%2 == 1
+}
+
+// MakeExactSet creates a new ExactSet from the included and the excluded numbers, which together
+// make up the universe. Contains is exact for the universe and garbage for other keys.
+// It panics if a key is both included and excluded.
+func MakeExactSet[T Number](included, excluded []T) ExactSet {
+	in := make([][2]uint32, 0, len(included))
+	seen := make(map[uint64]struct{}, len(included))
+	for _, k := range included {
+		a, b := bloomKey(uint64(k))
+		in = append(in, [2]uint32{a, b})
+		seen[uint64(k)] = struct{}{}
+	}
+	out := make([][2]uint32, 0, len(excluded))
+	for _, k := range excluded {
+		if _, ok := seen[uint64(k)]; ok {
+			panic("key is both included and excluded")
+		}
+		a, b := bloomKey(uint64(k))
+		out = append(out, [2]uint32{a, b})
+	}
+	return makeExactSet(in, out)
+}
+
+// MakeStringExactSet creates a new ExactSet from the included and the excluded strings.
+func MakeStringExactSet(included, excluded []string) ExactSet {
+	in := make([][2]uint32, 0, len(included))
+	seen := make(map[string]struct{}, len(included))
+	for _, k := range included {
+		a, b := bloomKeyString(k)
+		in = append(in, [2]uint32{a, b})
+		seen[k] = struct{}{}
+	}
+	out := make([][2]uint32, 0, len(excluded))
+	for _, k := range excluded {
+		if _, ok := seen[k]; ok {
+			panic("key is both included and excluded")
+		}
+		a, b := bloomKeyString(k)
+		out = append(out, [2]uint32{a, b})
+	}
+	return makeExactSet(in, out)
+}
+
+// Contains checks if an uint64 value is included in the ExactSet.
+func (s ExactSet) Contains(num uint64) bool {
+	return s.contains(bloomKey(num))
+}
+
+// ContainsInt checks if an int value is included in the ExactSet.
+func (s ExactSet) ContainsInt(num int) bool {
+	return s.Contains(uint64(num))
+}
+
+// ContainsString checks if a string is included in the ExactSet created by MakeStringExactSet.
+func (s ExactSet) ContainsString(str string) bool {
+	return s.contains(bloomKeyString(str))
+}
@@ -0,0 +1,74 @@
+package quaternary
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestExactSet(t *testing.T) {
+	var included, excluded []uint64
+	for i := uint64(0); i < 100000; i++ {
+		if i%97 == 0 {
+			included = append(included, i)
+		} else {
+			excluded = append(excluded, i)
+		}
+	}
+	s := MakeExactSet(included, excluded)
+	for _, k := range included {
+		if !s.Contains(k) {
+			t.Fatalf("Contains(%d) = false want true", k)
+		}
+	}
+	for _, k := range excluded {
+		if s.Contains(k) {
+			t.Fatalf("Contains(%d) = true want false", k)
+		}
+	}
+	// about 1.44*r*log2(s/r) bits for the first level and 4r bits for the rest of the cascade
+	if len(s)*8 > len(included)*16 {
+		t.Fatalf("ExactSet takes %d bytes for %d included keys", len(s), len(included))
+	}
+
+	var serials, revoked []string
+	for i := 0; i < 20000; i++ {
+		serials = append(serials, fmt.Sprintf("serial-%08x", i))
+		if i%10 == 0 {
+			revoked = append(revoked, fmt.Sprintf("serial-%08x", i+1<<30))
+		}
+	}
+	r := MakeStringExactSet(revoked, serials)
+	for _, k := range revoked {
+		if !r.ContainsString(k) {
+			t.Fatalf("ContainsString(%s) = false want true", k)
+		}
+	}
+	for _, k := range serials {
+		if r.ContainsString(k) {
+			t.Fatalf("ContainsString(%s) = true want false", k)
+		}
+	}
+
+	if MakeExactSet[int](nil, []int{1, 2}).ContainsInt(1) {
+		t.Fatalf("empty ExactSet contains a key")
+	}
+}
+
+func TestExactSetPanicsOnOverlap(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatalf("expected panic for a key both included and excluded")
+		}
+	}()
+	MakeExactSet([]int{1, 2}, []int{2, 3})
+}
+
+func TestExactSetTruncated(t *testing.T) {
+	s := MakeExactSet([]int{1, 5, 9}, []int{2, 3, 4, 6, 7, 8})
+	first := 4 + int(s[0])
+	for n := 0; n < len(s); n++ {
+		if s[:n].ContainsInt(1) && n > 4 && n < first {
+			t.Fatalf("truncated to %d bytes contains 1", n)
+		}
+	}
+}
@@ -17,19 +17,6 @@ func grow(n int) int {
 
 const ROUNDS = 64
 
-/*
-type Bloom []byte
-
-func (f Bloom) Put(num, salt uint32) {
-	idx := hash64(num, salt, uint64(len(f)*8))
-	(f)[idx>>3] |= 1 << (idx & 7)
-}
-
-func (f Bloom) Get(num, salt uint32) bool {
-	idx := hash64(num, salt, uint64(len(f)*8))
-	return ((f)[idx>>3]>>(idx&7))&1 == 1
-}
-*/
 // Filter is an immutable map without iterating capability.
 // It is used to store keys of various types and retrieve the corresponding booleans.
 type Filter []byte
@@ -218,6 +205,12 @@ func (fs Filters) insert(num uint64, answer uint64) (inserted []int) {
 }
 
 func (f Filter) store(data []byte, answer byte) (inserted int) {
+	return f.storeStrict(data, answer, false)
+}
+
+// storeStrict is store which, if strict, also marks the terminating cell when the answer
+// equals its parity, so that an empty cell proves the key was never stored
+func (f Filter) storeStrict(data []byte, answer byte, strict bool) (inserted int) {
 	if len(f) == 0 {
 		return 1
 	}
@@ -227,7 +220,7 @@ func (f Filter) store(data []byte, answer byte) (inserted int) {
 		h := hash64(dataHash(i, data), uint32(cells), uint64(cells)<<1)
 		switch ((f)[h>>3] >> (h & 6)) & 3 {
 		case 0:
-			if answer == byte(h&1) {
+			if answer == byte(h&1) && !strict {
 				return inserted
 			}
 			(f)[h>>3] |= ((answer & 1) + 1) << (h & 6)
@@ -251,6 +244,12 @@ func (f Filter) store(data []byte, answer byte) (inserted int) {
 }
 
 func (f Filter) insert(num uint64, answer byte) (inserted int) {
+	return f.insertStrict(num, answer, false)
+}
+
+// insertStrict is insert which, if strict, also marks the terminating cell when the answer
+// equals its parity, so that an empty cell proves the key was never inserted
+func (f Filter) insertStrict(num uint64, answer byte, strict bool) (inserted int) {
 	if len(f) == 0 {
 		return 1
 	}
@@ -262,7 +261,7 @@ func (f Filter) insert(num uint64, answer byte) (inserted int) {
 		h := hash64(x, high^i, uint64(cells))
 		switch ((f)[h>>2] >> ((h & 3) * 2)) & 3 {
 		case 0:
-			if answer == byte(x&1) {
+			if answer == byte(x&1) && !strict {
 				return inserted
 			}
 			(f)[h>>2] |= ((answer & 1) + 1) << ((h & 3) * 2)
@@ -548,59 +547,70 @@ type Number interface {
 // Make creates a new Filter from a map of numeric values.
 // The type T must satisfy the Number constraint.
 func Make[T Number](numbers map[T]bool) Filter {
-	return create(numbers, make(map[[64]byte]bool))[0]
+	return create(numbers, make(map[[64]byte]bool), false)[0]
 }
 
 // MakeBytes creates a new Filter from a map of 64-byte arrays.
 func MakeBytes(data map[[64]byte]bool) Filter {
-	return create(make(map[int]bool), data)[0]
+	return create(make(map[int]bool), data, false)[0]
 }
-func create[T Number](numbers map[T]bool, data map[[64]byte]bool) []Filter {
+func create[T Number](numbers map[T]bool, data map[[64]byte]bool, strict bool) []Filter {
 	if len(data)+len(numbers) == 0 {
 		return []Filter{nil}
 	}
-	bytes := byteSize(grow(len(data) + len(numbers)))
-	filter := make([]byte, bytes, bytes)
-	var maxLoad = len(data) + len(numbers)
+	return []Filter{createSized(numbers, data, strict, byteSize(grow(len(data)+len(numbers))))}
+}
+
+// createSized is create which starts with the given byte size and grows it as needed
+func createSized[T Number](numbers map[T]bool, data map[[64]byte]bool, strict bool, bytes int) Filter {
+	return fill(bytes, len(data)+len(numbers), func(filter Filter, budget int) (new_inserted int) {
+		for k, v := range data {
+			if v {
+				new_inserted += filter.storeStrict((k[:]), 1, strict)
+			} else {
+				new_inserted += filter.storeStrict((k[:]), 0, strict)
+			}
+			if new_inserted >= budget {
+				break
+			}
+		}
+		for k, v := range numbers {
+			if v {
+				new_inserted += filter.insertStrict(uint64(k), 1, strict)
+			} else {
+				new_inserted += filter.insertStrict(uint64(k), 0, strict)
+			}
+			if new_inserted >= budget {
+				break
+			}
+		}
+		return
+	})
+}
+
+// fill makes a filter of the given byte size and calls insert to store every key into it until
+// no cell changes, growing the filter when maxLoad cells change. insert returns the number of
+// cells it changed, it may stop early once that reaches budget.
+func fill(bytes, maxLoad int, insert func(filter Filter, budget int) int) Filter {
+	filter := make(Filter, bytes, bytes)
 	for {
 		var is_mutated = true
 		var load int
 		for is_mutated && load < maxLoad {
-			var new_inserted int
-			for k, v := range data {
-				if v {
-					new_inserted += Filter(filter).store((k[:]), 1)
-				} else {
-					new_inserted += Filter(filter).store((k[:]), 0)
-				}
-				if load+new_inserted >= maxLoad {
-					break
-				}
-			}
-			for k, v := range numbers {
-				if v {
-					new_inserted += Filter(filter).insert(uint64(k), 1)
-				} else {
-					new_inserted += Filter(filter).insert(uint64(k), 0)
-				}
-				if load+new_inserted >= maxLoad {
-					break
-				}
-			}
+			new_inserted := insert(filter, maxLoad-load)
 			is_mutated = is_mutated && new_inserted > 0
 			load += new_inserted
 			//println("inserted", new_inserted, "is_mutated", is_mutated, "load", load)
 		}
 		if is_mutated {
 			bytes = byteSize(grow(cellSize(bytes)))
-			filter = make([]byte, bytes, bytes)
+			filter = make(Filter, bytes, bytes)
 			maxLoad = grow(maxLoad)
 			//println("bytes", bytes, "maxLoad", maxLoad)
 		} else {
-			break
+			return filter
 		}
 	}
-	return []Filter{Filter(filter)}
 }
 func create64[T Number](filters byte, numbers map[T]uint64, data map[[64]byte]uint64) (filter []Filter) {
 	if len(data)+len(numbers) == 0 {
@@ -725,7 +735,7 @@ func MakeString(string_map map[string]bool) Filter {
 			data[stringsToByte64(k)] = v
 		}
 	}
-	return create(nums, data)[0]
+	return create(nums, data, false)[0]
 }
 
 // MakeStringMulti creates a new Filters from a map of strings.
@@ -750,5 +760,5 @@ func Make2Strings(string_map map[[2]string]bool) Filter {
 	for k, v := range string_map {
 		data[stringsToByte64(k[:]...)] = v
 	}
-	return create(make(map[int]bool), data)[0]
+	return create(make(map[int]bool), data, false)[0]
 }
@@ -0,0 +1,178 @@
+package quaternary
+
+import "encoding/binary"
+
+// GatedFilter is a Filter preceded by a Bloom region which detects absent keys.
+// The layout is a 4 byte little endian Bloom length, the Bloom and the Filter.
+type GatedFilter []byte
+
+// GatedFilters are Filters preceded by a Bloom stored as the first element.
+type GatedFilters [][]byte
+
+func gate(bloom Bloom, filter Filter) GatedFilter {
+	g := make(GatedFilter, 4, 4+len(bloom)+len(filter))
+	binary.LittleEndian.PutUint32(g, uint32(len(bloom)))
+	g = append(g, bloom...)
+	return append(g, filter...)
+}
+
+// bloomLen returns the length of the bloom region, ok is false if the GatedFilter is truncated
+func (g GatedFilter) bloomLen() (n uint64, ok bool) {
+	if len(g) < 4 {
+		return 0, false
+	}
+	n = uint64(binary.LittleEndian.Uint32(g))
+	return n, 4+n <= uint64(len(g))
+}
+
+// Bloom returns the bloom region of the GatedFilter, nil if it is truncated.
+func (g GatedFilter) Bloom() Bloom {
+	n, ok := g.bloomLen()
+	if !ok {
+		return nil
+	}
+	return Bloom(g[4 : 4+n])
+}
+
+// Filter returns the quaternary part of the GatedFilter, nil if it is truncated.
+func (g GatedFilter) Filter() Filter {
+	n, ok := g.bloomLen()
+	if !ok {
+		return nil
+	}
+	return Filter(g[4+n:])
+}
+
+// MakeGated creates a new GatedFilter from a map of numeric values.
+// About 2^-funcs of the keys which were not inserted are reported present.
+func MakeGated[T Number](numbers map[T]bool, funcs byte) GatedFilter {
+	bloom := makeBloom(len(numbers), funcs)
+	for k := range numbers {
+		bloom.put(bloomKey(uint64(k)))
+	}
+	return gate(bloom, Make(numbers))
+}
+
+// MakeBytesGated creates a new GatedFilter from a map of 64-byte arrays.
+func MakeBytesGated(data map[[64]byte]bool, funcs byte) GatedFilter {
+	bloom := makeBloom(len(data), funcs)
+	for k := range data {
+		bloom.put(bloomKeyBytes(k[:]))
+	}
+	return gate(bloom, MakeBytes(data))
+}
+
+// MakeStringGated creates a new GatedFilter from a map of strings.
+func MakeStringGated(string_map map[string]bool, funcs byte) GatedFilter {
+	bloom := makeBloom(len(string_map), funcs)
+	for k := range string_map {
+		bloom.putString(k)
+	}
+	return gate(bloom, MakeString(string_map))
+}
+
+// MakeStringMultiGated creates a new GatedFilters from a map of strings.
+func MakeStringMultiGated(multi byte, string_map map[string]uint64, funcs byte) GatedFilters {
+	bloom := makeBloom(len(string_map), funcs)
+	for k := range string_map {
+		bloom.putString(k)
+	}
+	r := GatedFilters{bloom}
+	for _, f := range MakeStringMulti(multi, string_map) {
+		r = append(r, f)
+	}
+	return r
+}
+
+// GetIntOK checks if an int value exists in the GatedFilter, and whether it was inserted.
+func (g GatedFilter) GetIntOK(num int) (value, present bool) {
+	return g.GetUint64OK(uint64(num))
+}
+
+// GetUintOK checks if an uint value exists in the GatedFilter, and whether it was inserted.
+func (g GatedFilter) GetUintOK(num uint) (value, present bool) {
+	return g.GetUint64OK(uint64(num))
+}
+
+// GetInt8OK checks if an int8 value exists in the GatedFilter, and whether it was inserted.
+func (g GatedFilter) GetInt8OK(num int8) (value, present bool) {
+	return g.GetUint64OK(uint64(num))
+}
+
+// GetUint8OK checks if an uint8 value exists in the GatedFilter, and whether it was inserted.
+func (g GatedFilter) GetUint8OK(num uint8) (value, present bool) {
+	return g.GetUint64OK(uint64(num))
+}
+
+// GetInt16OK checks if an int16 value exists in the GatedFilter, and whether it was inserted.
+func (g GatedFilter) GetInt16OK(num int16) (value, present bool) {
+	return g.GetUint64OK(uint64(num))
+}
+
+// GetUint16OK checks if an uint16 value exists in the GatedFilter, and whether it was inserted.
+func (g GatedFilter) GetUint16OK(num uint16) (value, present bool) {
+	return g.GetUint64OK(uint64(num))
+}
+
+// GetInt32OK checks if an int32 value exists in the GatedFilter, and whether it was inserted.
+func (g GatedFilter) GetInt32OK(num int32) (value, present bool) {
+	return g.GetUint64OK(uint64(num))
+}
+
+// GetUint32OK checks if an uint32 value exists in the GatedFilter, and whether it was inserted.
+func (g GatedFilter) GetUint32OK(num uint32) (value, present bool) {
+	return g.GetUint64OK(uint64(num))
+}
+
+// GetInt64OK checks if an int64 value exists in the GatedFilter, and whether it was inserted.
+func (g GatedFilter) GetInt64OK(num int64) (value, present bool) {
+	return g.GetUint64OK(uint64(num))
+}
+
+// GetUint64OK checks if an uint64 value exists in the GatedFilter, and whether it was inserted.
+func (g GatedFilter) GetUint64OK(num uint64) (value, present bool) {
+	if !g.Bloom().GetUint64(num) {
+		return false, false
+	}
+	return g.Filter().GetUint64(num), true
+}
+
+// GetBytesOK checks if a 64-byte array exists in the GatedFilter, and whether it was inserted.
+func (g GatedFilter) GetBytesOK(data [64]byte) (value, present bool) {
+	if !g.Bloom().GetBytes(data) {
+		return false, false
+	}
+	return g.Filter().GetBytes(data), true
+}
+
+// GetStringOK checks if a string exists in the GatedFilter, and whether it was inserted.
+func (g GatedFilter) GetStringOK(str string) (value, present bool) {
+	if !g.Bloom().GetString(str) {
+		return false, false
+	}
+	return g.Filter().GetString(str), true
+}
+
+// Bloom returns the bloom region of the GatedFilters.
+func (g GatedFilters) Bloom() Bloom {
+	if len(g) == 0 {
+		return nil
+	}
+	return Bloom(g[0])
+}
+
+// Filters returns the quaternary part of the GatedFilters.
+func (g GatedFilters) Filters() Filters {
+	if len(g) == 0 {
+		return nil
+	}
+	return Filters(g[1:])
+}
+
+// GetStringMultiOK checks if a string exists in the GatedFilters, and whether it was inserted.
+func (g GatedFilters) GetStringMultiOK(str string) (value uint64, present bool) {
+	if !g.Bloom().GetString(str) {
+		return 0, false
+	}
+	return g.Filters().GetStringMulti(str), true
+}
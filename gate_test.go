@@ -0,0 +1,121 @@
+package quaternary
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestGatedAbsentKeys(t *testing.T) {
+	const test = 10000
+	const funcs = 7
+
+	var nums = make(map[int]bool)
+	var strs = make(map[string]bool)
+	var multi = make(map[string]uint64)
+	for i := 0; i < test; i++ {
+		nums[i] = i%2 == 0
+		strs[fmt.Sprint("key", i)] = i%3 == 0
+		multi[fmt.Sprint("key", i)] = uint64(i % 16)
+	}
+	gn := MakeGated(nums, funcs)
+	gs := MakeStringGated(strs, funcs)
+	gm := MakeStringMultiGated(4, multi, funcs)
+
+	for k, v := range nums {
+		if got, ok := gn.GetIntOK(k); got != v || !ok {
+			t.Fatalf("GetIntOK(%d) = %v, %v want %v, true", k, got, ok, v)
+		}
+	}
+	for k, v := range strs {
+		if got, ok := gs.GetStringOK(k); got != v || !ok {
+			t.Fatalf("GetStringOK(%q) = %v, %v want %v, true", k, got, ok, v)
+		}
+	}
+	for k, v := range multi {
+		if got, ok := gm.GetStringMultiOK(k); got != v || !ok {
+			t.Fatalf("GetStringMultiOK(%q) = %v, %v want %v, true", k, got, ok, v)
+		}
+	}
+
+	var present [3]int
+	for i := test; i < 2*test; i++ {
+		if _, ok := gn.GetIntOK(i); ok {
+			present[0]++
+		}
+		if _, ok := gs.GetStringOK(fmt.Sprint("key", i)); ok {
+			present[1]++
+		}
+		if _, ok := gm.GetStringMultiOK(fmt.Sprint("key", i)); ok {
+			present[2]++
+		}
+	}
+	for i, p := range present {
+		// expect about test/2^funcs = 78
+		if p > 2*test>>funcs {
+			t.Fatalf("gate %d let %d of %d absent keys through", i, p, test)
+		}
+	}
+}
+
+func TestGatedBytes(t *testing.T) {
+	var m = make(map[[64]byte]bool)
+	for i := 0; i < 1000; i++ {
+		m[stringsToByte64(fmt.Sprint("bytes", i))] = i%2 == 1
+	}
+	g := MakeBytesGated(m, 10)
+	for k, v := range m {
+		if got, ok := g.GetBytesOK(k); got != v || !ok {
+			t.Fatalf("GetBytesOK = %v, %v want %v, true", got, ok, v)
+		}
+	}
+	var present int
+	for i := 1000; i < 2000; i++ {
+		if _, ok := g.GetBytesOK(stringsToByte64(fmt.Sprint("bytes", i))); ok {
+			present++
+		}
+	}
+	if present > 10 {
+		t.Fatalf("gate let %d of 1000 absent keys through", present)
+	}
+}
+
+func TestGatedEmpty(t *testing.T) {
+	g := MakeStringGated(map[string]bool{}, 5)
+	if _, ok := g.GetStringOK("a"); ok {
+		t.Fatalf("empty GatedFilter reported a key present")
+	}
+	if _, ok := MakeGated(map[int]bool{1: true}, 0).GetIntOK(2); !ok {
+		t.Fatalf("GatedFilter without bloom functions must report every key present")
+	}
+}
+
+func TestGatedTruncated(t *testing.T) {
+	g := MakeGated(map[int16]bool{1: true, -2: false, 3: true}, 5)
+	if v, ok := g.GetInt16OK(-2); !ok || v {
+		t.Fatalf("GetInt16OK(-2) = %v, %v want false, true", v, ok)
+	}
+	if v, ok := g.GetUint8OK(3); !ok || !v {
+		t.Fatalf("GetUint8OK(3) = %v, %v want true, true", v, ok)
+	}
+	for n := 0; n < len(g); n++ {
+		truncated := g[:n]
+		if n >= 4 && n < 4+len(g.Bloom()) && (truncated.Bloom() != nil || truncated.Filter() != nil) {
+			t.Fatalf("truncated to %d bytes returned a region", n)
+		}
+		if _, ok := truncated.GetInt16OK(1); ok && n < 4+len(g.Bloom()) {
+			t.Fatalf("truncated to %d bytes reported 1 present", n)
+		}
+	}
+	zeroed := make(GatedFilter, len(g))
+	if _, ok := zeroed.GetInt16OK(1); ok {
+		t.Fatalf("zeroed GatedFilter reported 1 present")
+	}
+	corrupt := append(GatedFilter{}, g...)
+	corrupt[4] = 0
+	if _, ok := corrupt.GetInt16OK(1); ok {
+		t.Fatalf("GatedFilter with a zero first bloom byte reported 1 present")
+	}
+	if _, ok := (GatedFilters{}).GetStringMultiOK("a"); ok {
+		t.Fatalf("empty GatedFilters reported a present")
+	}
+}
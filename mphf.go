@@ -0,0 +1,201 @@
+package quaternary
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+// MPHF is a minimal perfect hash function mapping n distinct keys to distinct numbers in [0, n).
+// It is a BDZ construction: every key is an edge of a random 3-hypergraph, the peeled graph
+// assigns every vertex a 2-bit cell selecting one of the three vertices of each key, and the
+// rank of the selected vertex among the assigned ones is the hash.
+//
+// The layout is a 4 byte little endian seed, a 4 byte little endian part size, the cells
+// in blocks of 256 and the 4 byte little endian rank of every block, about 2.6 bits per key.
+type MPHF []byte
+
+// unassigned is the cell value of vertices not selected by any key
+const unassigned = 3
+
+// mphfBlock is the number of cells per rank block
+const mphfBlock = 256
+
+// mphfMaxSeeds bounds the construction attempts, duplicate keys never make a peelable graph
+const mphfMaxSeeds = 64
+
+// mphfEdge returns the three vertices of a key, one in each part of size r
+func mphfEdge(num uint64, seed uint32, r uint32) (e [3]uint32) {
+	a, b := bloomKey(num + uint64(seed)*0x9e3779b97f4a7c15)
+	for i := uint32(0); i < 3; i++ {
+		e[i] = hash(a, b+i*0x9e3779b9, r) + i*r
+	}
+	return
+}
+
+// stringToMPHFKey reduces a string to the number its vertices are derived from
+func stringToMPHFKey(str string) uint64 {
+	if len(str) <= 7 {
+		return stringToUint64(str)
+	}
+	data := stringsToByte64(str)
+	return uint64(dataHash(ROUNDS+1, data[:]))<<32 | uint64(dataHash(ROUNDS, data[:]))
+}
+
+// peel orders the edges so that every edge has a vertex not contained in any edge after it,
+// it returns the order and the vertex of every edge or false if the graph isn't peelable
+func peel(edges [][3]uint32, vertices uint32) (order []uint32, free []byte, ok bool) {
+	degree := make([]uint32, vertices)
+	xored := make([]uint32, vertices)
+	for i, e := range edges {
+		for _, v := range e {
+			degree[v]++
+			xored[v] ^= uint32(i)
+		}
+	}
+	free = make([]byte, len(edges))
+	order = make([]uint32, 0, len(edges))
+	var stack []uint32
+	for v := range degree {
+		if degree[v] == 1 {
+			stack = append(stack, uint32(v))
+		}
+	}
+	for len(stack) > 0 {
+		v := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if degree[v] != 1 {
+			continue
+		}
+		i := xored[v]
+		order = append(order, i)
+		for j, u := range edges[i] {
+			if u == v {
+				free[i] = byte(j)
+			}
+			degree[u]--
+			xored[u] ^= i
+			if degree[u] == 1 {
+				stack = append(stack, u)
+			}
+		}
+	}
+	return order, free, len(order) == len(edges)
+}
+
+// buildMPHF builds the MPHF of distinct numeric keys
+func buildMPHF(keys []uint64) MPHF {
+	r := uint32((uint64(len(keys))*123+299)/300) + 1
+	for seed := uint32(0); seed < mphfMaxSeeds; seed++ {
+		edges := make([][3]uint32, len(keys))
+		for i, k := range keys {
+			edges[i] = mphfEdge(k, seed, r)
+		}
+		order, free, ok := peel(edges, 3*r)
+		if !ok {
+			// small graphs need more room to peel
+			r += r/32 + 1
+			continue
+		}
+		blocks := (3*r + mphfBlock - 1) / mphfBlock
+		f := make(MPHF, 8+blocks*mphfBlock/4+4*blocks)
+		binary.LittleEndian.PutUint32(f, seed)
+		binary.LittleEndian.PutUint32(f[4:], r)
+		cells := f[8 : 8+blocks*mphfBlock/4]
+		for i := range cells {
+			cells[i] = 0xff
+		}
+		get := func(v uint32) byte { return (cells[v>>2] >> ((v & 3) * 2)) & 3 }
+		for i := len(order) - 1; i >= 0; i-- {
+			e := edges[order[i]]
+			j := free[order[i]]
+			var sum byte
+			for k, v := range e {
+				if byte(k) != j {
+					sum += get(v)
+				}
+			}
+			// unassigned cells count as 0 as 3 = 0 mod 3
+			g := (j + 6 - sum%3) % 3
+			v := e[j]
+			cells[v>>2] &^= 3 << ((v & 3) * 2)
+			cells[v>>2] |= g << ((v & 3) * 2)
+		}
+		ranks := f[8+blocks*mphfBlock/4:]
+		var rank uint32
+		for b := uint32(0); b < blocks; b++ {
+			binary.LittleEndian.PutUint32(ranks[4*b:], rank)
+			rank += assignedIn(cells[b*mphfBlock/4:(b+1)*mphfBlock/4], mphfBlock)
+		}
+		return f
+	}
+	panic("keys must be distinct")
+}
+
+// assignedIn counts the assigned cells among the first n cells of data
+func assignedIn(data []byte, n uint32) (count uint32) {
+	for i := uint32(0); i < n; i += 32 {
+		var w uint64
+		if int(i/4)+8 <= len(data) {
+			w = binary.LittleEndian.Uint64(data[i/4:])
+		}
+		unused := w & (w >> 1) & 0x5555555555555555
+		cells := n - i
+		if cells < 32 {
+			unused &= 1<<(2*cells) - 1
+		} else {
+			cells = 32
+		}
+		count += cells - uint32(bits.OnesCount64(unused))
+	}
+	return
+}
+
+// BuildMPHF creates a new MPHF of distinct numeric keys, mapping them to [0, len(keys)).
+// It panics if the keys aren't distinct.
+func BuildMPHF[T Number](keys []T) MPHF {
+	nums := make([]uint64, len(keys))
+	for i, k := range keys {
+		nums[i] = uint64(k)
+	}
+	return buildMPHF(nums)
+}
+
+// BuildStringMPHF creates a new MPHF of distinct strings, mapping them to [0, len(keys)).
+func BuildStringMPHF(keys []string) MPHF {
+	nums := make([]uint64, len(keys))
+	for i, k := range keys {
+		nums[i] = stringToMPHFKey(k)
+	}
+	return buildMPHF(nums)
+}
+
+// LookupUint64 returns the number of an uint64 key, garbage for keys the MPHF wasn't built from.
+func (f MPHF) LookupUint64(num uint64) uint64 {
+	if len(f) < 8 {
+		return 0
+	}
+	seed := binary.LittleEndian.Uint32(f)
+	r := binary.LittleEndian.Uint32(f[4:])
+	blocks := (3*r + mphfBlock - 1) / mphfBlock
+	cells := f[8 : 8+blocks*mphfBlock/4]
+	ranks := f[8+blocks*mphfBlock/4:]
+	e := mphfEdge(num, seed, r)
+	var sum byte
+	for _, v := range e {
+		sum += (cells[v>>2] >> ((v & 3) * 2)) & 3
+	}
+	v := e[sum%3]
+	b := v / mphfBlock
+	rank := binary.LittleEndian.Uint32(ranks[4*b:])
+	return uint64(rank + assignedIn(cells[b*mphfBlock/4:], v%mphfBlock))
+}
+
+// LookupInt returns the number of an int key.
+func (f MPHF) LookupInt(num int) uint64 {
+	return f.LookupUint64(uint64(num))
+}
+
+// LookupString returns the number of a string key of a MPHF built by BuildStringMPHF.
+func (f MPHF) LookupString(str string) uint64 {
+	return f.LookupUint64(stringToMPHFKey(str))
+}
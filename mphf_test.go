@@ -0,0 +1,76 @@
+package quaternary
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestMPHF(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 10, 1000, 100000} {
+		keys := make([]uint64, n)
+		for i := range keys {
+			keys[i] = uint64(i)*0x9e3779b97f4a7c15 + 12345
+		}
+		f := BuildMPHF(keys)
+		seen := make([]bool, n)
+		for _, k := range keys {
+			h := f.LookupUint64(k)
+			if h >= uint64(n) {
+				t.Fatalf("n %d: Lookup(%d) = %d out of range", n, k, h)
+			}
+			if seen[h] {
+				t.Fatalf("n %d: Lookup(%d) = %d collides", n, k, h)
+			}
+			seen[h] = true
+		}
+		if n >= 1000 {
+			bitsPerKey := float64(len(f)*8) / float64(n)
+			t.Logf("n %d: %.2f bits per key", n, bitsPerKey)
+			if bitsPerKey > 3 {
+				t.Fatalf("n %d: %.2f bits per key", n, bitsPerKey)
+			}
+		}
+	}
+}
+
+func TestStringMPHF(t *testing.T) {
+	keys := make([]string, 50000)
+	for i := range keys {
+		keys[i] = fmt.Sprint("key", i)
+	}
+	f := BuildStringMPHF(keys)
+	values := make([]string, len(keys))
+	for _, k := range keys {
+		values[f.LookupString(k)] = k
+	}
+	for _, k := range keys {
+		if values[f.LookupString(k)] != k {
+			t.Fatalf("LookupString(%s) indexes %s", k, values[f.LookupString(k)])
+		}
+	}
+	ints := BuildMPHF([]int{-1, 5, 7})
+	if ints.LookupInt(-1)+ints.LookupInt(5)+ints.LookupInt(7) != 3 {
+		t.Fatalf("LookupInt isn't a permutation of 0, 1, 2")
+	}
+}
+
+func TestMPHFPanicsOnDuplicates(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatalf("expected panic for duplicate keys")
+		}
+	}()
+	BuildMPHF([]int{1, 2, 2})
+}
+
+func BenchmarkMPHFLookup(b *testing.B) {
+	keys := make([]uint64, 100000)
+	for i := range keys {
+		keys[i] = uint64(i)
+	}
+	f := BuildMPHF(keys)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		f.LookupUint64(uint64(i % len(keys)))
+	}
+}
@@ -0,0 +1,84 @@
+package quaternary
+
+// multi converts the Filters created by create64 to Filters
+func multi(fs []Filter) Filters {
+	r := make(Filters, len(fs))
+	for i := range fs {
+		r[i] = fs[i]
+	}
+	return r
+}
+
+// checkLabels panics if a value of the map doesn't fit in bits
+func checkLabels[K comparable](bits byte, values map[K]uint64) {
+	if bits > 64 {
+		panic("multi filters hold at most 64 bits")
+	}
+	if bits == 64 {
+		return
+	}
+	for _, v := range values {
+		if v>>bits != 0 {
+			panic("value doesn't fit in the bits of the multi filters")
+		}
+	}
+}
+
+// MakeMulti creates new Filters holding bits wide values from a map of numeric values.
+// The type T must satisfy the Number constraint. It panics if a value doesn't fit in bits.
+func MakeMulti[T Number](bits byte, numbers map[T]uint64) []Filter {
+	checkLabels(bits, numbers)
+	return create64(bits, numbers, make(map[[64]byte]uint64))
+}
+
+// MakeBytesMulti creates new Filters holding bits wide values from a map of 64-byte arrays.
+// It panics if a value doesn't fit in bits.
+func MakeBytesMulti(bits byte, data map[[64]byte]uint64) []Filter {
+	checkLabels(bits, data)
+	return create64(bits, make(map[int]uint64), data)
+}
+
+// GetIntMulti retrieves the value of an int key from the Filters.
+func (f Filters) GetIntMulti(num int) uint64 {
+	return f.GetUint64Multi(uint64(num))
+}
+
+// GetUintMulti retrieves the value of an uint key from the Filters.
+func (f Filters) GetUintMulti(num uint) uint64 {
+	return f.GetUint64Multi(uint64(num))
+}
+
+// GetInt8Multi retrieves the value of an int8 key from the Filters.
+func (f Filters) GetInt8Multi(num int8) uint64 {
+	return f.GetUint64Multi(uint64(num))
+}
+
+// GetUint8Multi retrieves the value of an uint8 key from the Filters.
+func (f Filters) GetUint8Multi(num uint8) uint64 {
+	return f.GetUint64Multi(uint64(num))
+}
+
+// GetInt16Multi retrieves the value of an int16 key from the Filters.
+func (f Filters) GetInt16Multi(num int16) uint64 {
+	return f.GetUint64Multi(uint64(num))
+}
+
+// GetUint16Multi retrieves the value of an uint16 key from the Filters.
+func (f Filters) GetUint16Multi(num uint16) uint64 {
+	return f.GetUint64Multi(uint64(num))
+}
+
+// GetInt32Multi retrieves the value of an int32 key from the Filters.
+func (f Filters) GetInt32Multi(num int32) uint64 {
+	return f.GetUint64Multi(uint64(num))
+}
+
+// GetUint32Multi retrieves the value of an uint32 key from the Filters.
+func (f Filters) GetUint32Multi(num uint32) uint64 {
+	return f.GetUint64Multi(uint64(num))
+}
+
+// GetInt64Multi retrieves the value of an int64 key from the Filters.
+func (f Filters) GetInt64Multi(num int64) uint64 {
+	return f.GetUint64Multi(uint64(num))
+}
@@ -0,0 +1,68 @@
+package quaternary
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestMakeMulti(t *testing.T) {
+	const test = 10000
+
+	var ids = make(map[int64]uint64)
+	var ints = make(map[int]uint64)
+	var uuids = make(map[[64]byte]uint64)
+	for i := 0; i < test; i++ {
+		ids[int64(i)*7919-test] = uint64(i % 13)
+		ints[i] = uint64(i*i) & 0xffff
+		var uuid [64]byte
+		copy(uuid[:], fmt.Sprintf("%016x", i*104729))
+		uuids[uuid] = uint64(i % 5)
+	}
+
+	fi := multi(MakeMulti(4, ids))
+	for k, v := range ids {
+		if got := fi.GetInt64Multi(k); got != v {
+			t.Fatalf("GetInt64Multi(%d) = %d want %d", k, got, v)
+		}
+	}
+	fn := multi(MakeMulti(16, ints))
+	for k, v := range ints {
+		if got := fn.GetIntMulti(k); got != v {
+			t.Fatalf("GetIntMulti(%d) = %d want %d", k, got, v)
+		}
+	}
+	fb := multi(MakeBytesMulti(3, uuids))
+	for k, v := range uuids {
+		if got := fb.GetBytesMulti(k); got != v {
+			t.Fatalf("GetBytesMulti(%x) = %d want %d", k[:16], got, v)
+		}
+	}
+}
+
+func TestMakeMultiSmallKeys(t *testing.T) {
+	f := multi(MakeMulti(8, map[uint8]uint64{0: 200, 1: 3, 255: 77}))
+	for k, v := range map[uint8]uint64{0: 200, 1: 3, 255: 77} {
+		if got := f.GetUint8Multi(k); got != v {
+			t.Fatalf("GetUint8Multi(%d) = %d want %d", k, got, v)
+		}
+	}
+	if len(MakeMulti(5, map[int]uint64{})) != 5 {
+		t.Fatalf("empty MakeMulti must return one filter per bit")
+	}
+}
+
+func TestMakeMultiRejectsWideValues(t *testing.T) {
+	for name, build := range map[string]func(){
+		"numbers": func() { MakeMulti(4, map[int]uint64{1: 3, 2: 16}) },
+		"bytes":   func() { MakeBytesMulti(1, map[[64]byte]uint64{{1}: 2}) },
+	} {
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Fatalf("%s: expected panic for a value wider than bits", name)
+				}
+			}()
+			build()
+		}()
+	}
+}
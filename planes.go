@@ -0,0 +1,122 @@
+package quaternary
+
+// Planes are the bit planes of multi-bit values, like Filters, but every plane is sized
+// and grown independently. Planes where one bit value dominates need far fewer cells
+// than the worst plane, which pays off for skewed labels.
+type Planes [][]byte
+
+// createPlane creates a single bit plane, starting at half the size create would use
+func createPlane[T Number](numbers map[T]bool, data map[[64]byte]bool) Filter {
+	n := len(data) + len(numbers)
+	if n == 0 {
+		return nil
+	}
+	var ones int
+	for _, v := range numbers {
+		if v {
+			ones++
+		}
+	}
+	for _, v := range data {
+		if v {
+			ones++
+		}
+	}
+	if ones == 0 || ones == n {
+		// constant planes never conflict
+		return createSized(numbers, data, false, 1)
+	}
+	bytes := byteSize(grow(n)) / 2
+	if bytes < 1 {
+		bytes = 1
+	}
+	return createSized(numbers, data, false, bytes)
+}
+
+func createPlanes[T Number](bits byte, numbers map[T]uint64, data map[[64]byte]uint64) Planes {
+	if bits > 64 {
+		panic("planes hold at most 64 bits")
+	}
+	checkLabels(bits, numbers)
+	checkLabels(bits, data)
+	ret := make(Planes, bits)
+	for i := range ret {
+		var planeNumbers = make(map[T]bool, len(numbers))
+		for k, v := range numbers {
+			planeNumbers[k] = (v>>uint(i))&1 == 1
+		}
+		var planeData = make(map[[64]byte]bool, len(data))
+		for k, v := range data {
+			planeData[k] = (v>>uint(i))&1 == 1
+		}
+		ret[i] = createPlane(planeNumbers, planeData)
+	}
+	return ret
+}
+
+// MakePlanes creates new Planes holding bits wide values from a map of numeric values.
+// The type T must satisfy the Number constraint. It panics if a value doesn't fit in bits.
+func MakePlanes[T Number](bits byte, numbers map[T]uint64) Planes {
+	return createPlanes(bits, numbers, make(map[[64]byte]uint64))
+}
+
+// MakeBytesPlanes creates new Planes holding bits wide values from a map of 64-byte arrays.
+// It panics if a value doesn't fit in bits.
+func MakeBytesPlanes(bits byte, data map[[64]byte]uint64) Planes {
+	return createPlanes(bits, make(map[int]uint64), data)
+}
+
+// MakeStringPlanes creates new Planes holding bits wide values from a map of strings.
+func MakeStringPlanes(bits byte, string_map map[string]uint64) Planes {
+	var data = make(map[[64]byte]uint64)
+	var nums = make(map[uint64]uint64)
+	for k, v := range string_map {
+		if len(k) <= 7 {
+			nums[stringToUint64(k)] = v
+		} else {
+			data[stringsToByte64(k)] = v
+		}
+	}
+	return createPlanes(bits, nums, data)
+}
+
+// Size returns the total byte size of the Planes.
+func (p Planes) Size() (size int) {
+	for _, f := range p {
+		size += len(f)
+	}
+	return
+}
+
+// GetInt retrieves the value of an int key from the Planes.
+func (p Planes) GetInt(num int) uint64 {
+	return p.GetUint64(uint64(num))
+}
+
+// GetUint64 retrieves the value of an uint64 key from the Planes.
+func (p Planes) GetUint64(num uint64) (ret uint64) {
+	for i, f := range p {
+		if Filter(f).GetUint64(num) {
+			ret |= 1 << uint(i)
+		}
+	}
+	return
+}
+
+// GetBytes retrieves the value of a 64-byte array key from the Planes.
+func (p Planes) GetBytes(data [64]byte) (ret uint64) {
+	for i, f := range p {
+		if Filter(f).GetBytes(data) {
+			ret |= 1 << uint(i)
+		}
+	}
+	return
+}
+
+// GetString retrieves the value of a string key from the Planes created by MakeStringPlanes.
+func (p Planes) GetString(str string) uint64 {
+	if len(str) <= 7 {
+		return p.GetUint64(stringToUint64(str))
+	}
+	return p.GetBytes(stringsToByte64(str))
+}
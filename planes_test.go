@@ -0,0 +1,90 @@
+package quaternary
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+// skewedLabels returns n 8 bit labels where 90% of keys carry one of three labels
+func skewedLabels(n int) map[string]uint64 {
+	rnd := rand.New(rand.NewSource(1))
+	var m = make(map[string]uint64, n)
+	for i := 0; i < n; i++ {
+		label := uint64(rnd.Intn(3))
+		if rnd.Intn(10) == 0 {
+			label = uint64(rnd.Intn(200))
+		}
+		m[fmt.Sprint("label key ", i)] = label
+	}
+	return m
+}
+
+func TestMakePlanes(t *testing.T) {
+	const test = 10000
+
+	var nums = make(map[int]uint64)
+	for i := 0; i < test; i++ {
+		nums[i] = uint64(i*i) & 0xfff
+	}
+	fn := MakePlanes(12, nums)
+	for k, v := range nums {
+		if got := fn.GetInt(k); got != v {
+			t.Fatalf("GetInt(%d) = %d want %d", k, got, v)
+		}
+	}
+
+	strs := skewedLabels(test)
+	fs := MakeStringPlanes(8, strs)
+	for k, v := range strs {
+		if got := fs.GetString(k); got != v {
+			t.Fatalf("GetString(%q) = %d want %d", k, got, v)
+		}
+	}
+	if multi := MakeStringMulti(8, strs); fs.Size() >= 8*len(multi[0]) {
+		t.Fatalf("Planes size %d not below Filters size %d", fs.Size(), 8*len(multi[0]))
+	}
+
+	var bytes = make(map[[64]byte]uint64)
+	for i := 0; i < 1000; i++ {
+		bytes[stringsToByte64(fmt.Sprint("bytes", i))] = uint64(i % 7)
+	}
+	fb := MakeBytesPlanes(3, bytes)
+	for k, v := range bytes {
+		if got := fb.GetBytes(k); got != v {
+			t.Fatalf("GetBytes = %d want %d", got, v)
+		}
+	}
+}
+
+func BenchmarkSizeSkewedPlanes(b *testing.B) {
+	for _, n := range []int{1000, 10000, 100000} {
+		m := skewedLabels(n)
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			var planes, filters int
+			for i := 0; i < b.N; i++ {
+				planes = MakeStringPlanes(8, m).Size()
+				filters = 8 * len(MakeStringMulti(8, m)[0])
+			}
+			b.ReportMetric(float64(planes), "planes-bytes")
+			b.ReportMetric(float64(filters), "filters-bytes")
+		})
+	}
+}
+
+func TestMakePlanesRejectsWideValues(t *testing.T) {
+	for name, build := range map[string]func(){
+		"numbers": func() { MakePlanes(4, map[int]uint64{1: 3, 2: 16}) },
+		"bytes":   func() { MakeBytesPlanes(1, map[[64]byte]uint64{{1}: 2}) },
+		"string":  func() { MakeStringPlanes(2, map[string]uint64{"a long key": 4}) },
+	} {
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Fatalf("%s: expected panic for a value wider than bits", name)
+				}
+			}()
+			build()
+		}()
+	}
+}
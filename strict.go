@@ -0,0 +1,98 @@
+package quaternary
+
+// StrictFilter is a Filter built in strict mode, where every key marks the cell its lookup
+// terminates on. A lookup reaching an empty cell therefore proves the key was never inserted,
+// which detects part of the absent keys without a separate bloom region.
+//
+// Strict mode writes a cell for every key instead of about half of them,
+// so a StrictFilter is usually one to two grow steps (1.5x to 2.25x) larger than a Filter.
+type StrictFilter []byte
+
+// Filter returns the StrictFilter as a Filter, usable with the plain lookups.
+func (f StrictFilter) Filter() Filter {
+	return Filter(f)
+}
+
+// MakeStrict creates a new StrictFilter from a map of numeric values.
+func MakeStrict[T Number](numbers map[T]bool) StrictFilter {
+	return StrictFilter(create(numbers, make(map[[64]byte]bool), true)[0])
+}
+
+// MakeBytesStrict creates a new StrictFilter from a map of 64-byte arrays.
+func MakeBytesStrict(data map[[64]byte]bool) StrictFilter {
+	return StrictFilter(create(make(map[int]bool), data, true)[0])
+}
+
+// MakeStringStrict creates a new StrictFilter from a map of strings.
+func MakeStringStrict(string_map map[string]bool) StrictFilter {
+	var data = make(map[[64]byte]bool)
+	var nums = make(map[uint64]bool)
+	for k, v := range string_map {
+		if len(k) <= 7 {
+			nums[stringToUint64(k)] = v
+		} else {
+			data[stringsToByte64(k)] = v
+		}
+	}
+	return StrictFilter(create(nums, data, true)[0])
+}
+
+// GetIntOK checks if an int value exists in the StrictFilter, present is false if it surely wasn't inserted.
+func (f StrictFilter) GetIntOK(num int) (value, present bool) {
+	return f.GetUint64OK(uint64(num))
+}
+
+// GetUint64OK checks if an uint64 value exists in the StrictFilter, present is false if it surely wasn't inserted.
+func (f StrictFilter) GetUint64OK(num uint64) (value, present bool) {
+	if len(f) == 0 {
+		return false, false
+	}
+	cells := cellSize(len(f))
+	x := uint32(num)
+	high := uint32(num >> 32)
+	for i := uint32(0); i < ROUNDS; i++ {
+		h := hash64(x, high^i, uint64(cells))
+		switch ((f)[h>>2] >> ((h & 3) * 2)) & 3 {
+		case 0:
+			return false, false
+		case 1:
+			return false, true
+		case 2:
+			return true, true
+		case 3:
+			x = (x >> 1) | (x << 31)
+		}
+	}
+	return false, false
+}
+
+// GetBytesOK checks if a 64-byte array exists in the StrictFilter, present is false if it surely wasn't inserted.
+func (f StrictFilter) GetBytesOK(data [64]byte) (value, present bool) {
+	if len(f) == 0 {
+		return false, false
+	}
+	cells := cellSize(len(f))
+	for i := uint32(0); i < ROUNDS; i++ {
+		h := hash64(dataHash(i, data[:]), uint32(cells), uint64(cells)<<1)
+		switch ((f)[h>>3] >> (h & 6)) & 3 {
+		case 0:
+			return false, false
+		case 1:
+			return false, true
+		case 2:
+			return true, true
+		case 3:
+			continue
+		}
+	}
+	return false, false
+}
+
+// GetStringOK checks if a string exists in the StrictFilter created by MakeStringStrict,
+// present is false if it surely wasn't inserted.
+func (f StrictFilter) GetStringOK(str string) (value, present bool) {
+	if len(str) <= 7 {
+		return f.GetUint64OK(stringToUint64(str))
+	}
+	return f.GetBytesOK(stringsToByte64(str))
+}
@@ -0,0 +1,66 @@
+package quaternary
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestStrictAbsentKeys(t *testing.T) {
+	const test = 10000
+
+	var nums = make(map[int]bool)
+	var strs = make(map[string]bool)
+	for i := 0; i < test; i++ {
+		nums[i] = i%2 == 0
+		strs[fmt.Sprint("strict key ", i)] = i%3 == 0
+	}
+	fn := MakeStrict(nums)
+	fs := MakeStringStrict(strs)
+
+	for k, v := range nums {
+		if got, ok := fn.GetIntOK(k); got != v || !ok {
+			t.Fatalf("GetIntOK(%d) = %v, %v want %v, true", k, got, ok, v)
+		}
+		if got := fn.Filter().GetInt(k); got != v {
+			t.Fatalf("Filter().GetInt(%d) = %v want %v", k, got, v)
+		}
+	}
+	for k, v := range strs {
+		if got, ok := fs.GetStringOK(k); got != v || !ok {
+			t.Fatalf("GetStringOK(%q) = %v, %v want %v, true", k, got, ok, v)
+		}
+	}
+
+	var absent [2]int
+	for i := test; i < 2*test; i++ {
+		if _, ok := fn.GetIntOK(i); !ok {
+			absent[0]++
+		}
+		if _, ok := fs.GetStringOK(fmt.Sprint("strict key ", i)); !ok {
+			absent[1]++
+		}
+	}
+	for i, a := range absent {
+		if a == 0 {
+			t.Fatalf("strict filter %d detected none of the absent keys", i)
+		}
+	}
+	fmt.Printf("[Strict] detected %d and %d of %d absent keys, size %d vs %d and %d vs %d bytes\n",
+		absent[0], absent[1], test, len(fn), len(Make(nums)), len(fs), len(MakeString(strs)))
+}
+
+func TestStrictBytes(t *testing.T) {
+	var m = make(map[[64]byte]bool)
+	for i := 0; i < 1000; i++ {
+		m[stringsToByte64(fmt.Sprint("bytes", i))] = i%2 == 1
+	}
+	f := MakeBytesStrict(m)
+	for k, v := range m {
+		if got, ok := f.GetBytesOK(k); got != v || !ok {
+			t.Fatalf("GetBytesOK = %v, %v want %v, true", got, ok, v)
+		}
+	}
+	if _, ok := MakeStringStrict(nil).GetStringOK("a"); ok {
+		t.Fatalf("empty StrictFilter reported a key present")
+	}
+}
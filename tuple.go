@@ -0,0 +1,58 @@
+package quaternary
+
+import (
+	"crypto/sha512"
+	"encoding/binary"
+)
+
+// Tuple is a type constraint that represents string tuples of one to eight parts.
+type Tuple interface {
+	~[1]string | ~[2]string | ~[3]string | ~[4]string | ~[5]string | ~[6]string | ~[7]string | ~[8]string
+}
+
+// TupleEncoding selects how the parts of a tuple key are combined into a 64-byte key.
+type TupleEncoding byte
+
+const (
+	// Commutative sums the SHA-512 hashes of the parts like Make2Strings and GetStrings,
+	// so {"a", "b"} and {"b", "a"} are the same key. Use it for filters built by Make2Strings.
+	Commutative TupleEncoding = iota
+	// Ordered hashes the length prefixed parts in order, so every distinct tuple is a distinct key.
+	Ordered
+)
+
+// tupleToByte64 builds a 64-byte key from the parts of a tuple
+func tupleToByte64(enc TupleEncoding, parts ...string) (ret [64]byte) {
+	if enc == Commutative {
+		return stringsToByte64(parts...)
+	}
+	h := sha512.New()
+	var n [binary.MaxVarintLen64]byte
+	for _, str := range parts {
+		h.Write(n[:binary.PutUvarint(n[:], uint64(len(str)))])
+		h.Write([]byte(str))
+	}
+	h.Sum(ret[:0])
+	return ret
+}
+
+// MakeTuple creates a new Filter from a map of string tuples of one to eight parts.
+// With the Commutative encoding the Filter is looked up like the ones built by Make2Strings.
+func MakeTuple[K Tuple](tuple_map map[K]bool, enc TupleEncoding) Filter {
+	var data = make(map[[64]byte]bool)
+	var parts []string
+	for k, v := range tuple_map {
+		parts = parts[:0]
+		for i := 0; i < len(k); i++ {
+			parts = append(parts, k[i])
+		}
+		data[tupleToByte64(enc, parts...)] = v
+	}
+	return create(make(map[int]bool), data, false)[0]
+}
+
+// GetTuple checks the provided tuple parts exist in the Filter created by MakeTuple with the same encoding.
+// Tuples of more than eight parts can't be inserted, looking them up returns a garbage value.
+func (f Filter) GetTuple(enc TupleEncoding, parts ...string) bool {
+	return f.GetBytes(tupleToByte64(enc, parts...))
+}
@@ -0,0 +1,68 @@
+package quaternary
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestTupleOrdered(t *testing.T) {
+	const test = 1000
+
+	var pairs = make(map[[2]string]bool)
+	var triples = make(map[[3]string]bool)
+	for i := 0; i < test; i++ {
+		src, dst := fmt.Sprint("src", i), fmt.Sprint("dst", i)
+		pairs[[2]string{src, dst}] = true
+		pairs[[2]string{dst, src}] = false
+		triples[[3]string{src, dst, "x"}] = i%2 == 0
+		triples[[3]string{"x", dst, src}] = i%2 == 1
+	}
+	fp := MakeTuple(pairs, Ordered)
+	for k, v := range pairs {
+		if got := fp.GetTuple(Ordered, k[:]...); got != v {
+			t.Fatalf("GetTuple(%q) = %v want %v", k, got, v)
+		}
+	}
+	ft := MakeTuple(triples, Ordered)
+	for k, v := range triples {
+		if got := ft.GetTuple(Ordered, k[:]...); got != v {
+			t.Fatalf("GetTuple(%q) = %v want %v", k, got, v)
+		}
+	}
+
+	if tupleToByte64(Ordered, "ab", "c") == tupleToByte64(Ordered, "a", "bc") {
+		t.Fatalf("ordered encoding ignores part boundaries")
+	}
+	if tupleToByte64(Ordered, "a", "b") == tupleToByte64(Ordered, "b", "a") {
+		t.Fatalf("ordered encoding ignores part order")
+	}
+}
+
+func TestTupleCommutativeMigration(t *testing.T) {
+	var m = map[[2]string]bool{
+		{"a", ""}:     true,
+		{"b", ""}:     false,
+		{"", "0"}:     true,
+		{"foo", "ba"}: false,
+	}
+	old := Make2Strings(m)
+	tuple := MakeTuple(m, Commutative)
+	for k, v := range m {
+		if got := old.GetTuple(Commutative, k[:]...); got != v {
+			t.Fatalf("GetTuple(%q) = %v want %v", k, got, v)
+		}
+		if got := tuple.GetStrings(k[:]...); got != v {
+			t.Fatalf("GetStrings(%q) = %v want %v", k, got, v)
+		}
+	}
+}
+
+func TestTupleLimit(t *testing.T) {
+	f := MakeTuple(map[[8]string]bool{{"1", "2", "3", "4", "5", "6", "7", "8"}: true}, Ordered)
+	if !f.GetTuple(Ordered, "1", "2", "3", "4", "5", "6", "7", "8") {
+		t.Fatalf("eight part tuple not found")
+	}
+	// longer lookups are keys like any other
+	f.GetTuple(Ordered, "1", "2", "3", "4", "5", "6", "7", "8", "9")
+	f.GetTuple(Commutative, "1", "2", "3", "4", "5", "6", "7", "8", "9")
+}
@@ -8,11 +8,17 @@ import "math"
 
 const Unlimited byte = 0
 
+// Value is the set of value types a filter can store.
+// Signed integers are stored sign-extended to bitLimit bits, floats as their leading bits.
+// Creation panics if a signed integer doesn't fit in bitLimit bits, unsigned integers keep their low bits.
+type Value interface {
+	string | []byte | bool |
+		uint | uint64 | uint32 | uint16 | uint8 |
+		int | int64 | int32 | int16 | int8 |
+		float64 | float32
+}
+
 func comparableToBytes[T comparable](v T) []byte {
-	rv := reflect.ValueOf(v)
-	if rv.Kind() == reflect.Pointer && rv.IsNil() {
-		return nil
-	}
 	switch val := any(v).(type) {
 	case string:
 		return []byte(val)
@@ -75,6 +81,19 @@ func comparableToBytes[T comparable](v T) []byte {
 		return b[:]
 	}
 
+	if enc, ok := any(v).(KeyEncoder); ok {
+		return enc.AppendKey(nil)
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Pointer && rv.IsNil() {
+		return nil
+	}
+	if rv.IsValid() {
+		if enc, ok := keyEncoders.Load(rv.Type()); ok {
+			return enc.(func(dst []byte, key any) []byte)(nil, v)
+		}
+	}
+
 	bytes, err := json.Marshal(v)
 	if err == nil {
 		return bytes
@@ -84,25 +103,73 @@ func comparableToBytes[T comparable](v T) []byte {
 }
 
 // New generates the map based on map m with garbage rate dependent on bloomFuncs
-func New[K comparable, V string | []byte | bool | uint64 | uint32 | uint16 | uint8](m map[K]V, bitLimit, bloomFuncs byte) []byte {
+func New[K comparable, V Value](m map[K]V, bitLimit, bloomFuncs byte) []byte {
+	pairs := materialize(m, &bitLimit)
+
+	// handle the empty pairs case (empty map or all values were empty)
+	if len(pairs) == 0 {
+		return []byte{bloomFuncs, bitLimit}
+	}
+
+	// real impl
+	return create(pairs.iter, bitLimit, bloomFuncs)
+}
+
+// pairs are materialized key-value pairs ready for create
+type pairs [][2][]byte
+
+// iter yields the materialized pairs, it can be called multiple times
+func (p pairs) iter(yield func(kvPair [2][]byte) bool) {
+	for i := range p {
+		if !yield(p[i]) {
+			return
+		}
+	}
+}
+
+// materialize converts map m into key-value pairs once to avoid repeated conversions,
+// it adjusts bitLimit for bool typed values
+func materialize[K comparable, V Value](m map[K]V, bitLimit *byte) pairs {
+	return materializeKeys(m, bitLimit, comparableToBytes[K])
+}
+
+// checkKeyType panics for pointer key types without a KeyEncoder or a registered encoder,
+// as distinct pointers to equal values would be encoded identically by their pointees
+func checkKeyType[K comparable]() {
+	t := reflect.TypeOf((*K)(nil)).Elem()
+	if t.Kind() != reflect.Pointer || t.Implements(reflect.TypeOf((*KeyEncoder)(nil)).Elem()) {
+		return
+	}
+	if _, ok := keyEncoders.Load(t); !ok {
+		panic("pointer keys are not supported, use the pointee or a KeyEncoder")
+	}
+}
+
+// materializeKeys is materialize which encodes the keys using encode
+func materializeKeys[K comparable, V Value](m map[K]V, bitLimit *byte, encode func(K) []byte) pairs {
+	checkKeyType[K]()
 	// Check if map is empty
 	if len(m) == 0 {
-		return []byte{bloomFuncs, bitLimit}
+		return nil
 	}
 
 	// Adjust bitLimit for bool type
 	for _, v := range m {
 		if _, ok := any(v).(bool); ok {
-			bitLimit = 1
+			*bitLimit = 1
 			break
 		}
 	}
 
-	// Materialize key-value pairs once to avoid repeated conversions
-	pairs := make([][2][]byte, 0, len(m))
+	ret := make(pairs, 0, len(m))
+	encoded := make(map[string]struct{}, len(m))
 	for k, v := range m {
 		var kv [2][]byte
-		*kvPairKey(&kv) = comparableToBytes(k)
+		*kvPairKey(&kv) = encode(k)
+		if _, ok := encoded[string(*kvPairKey(&kv))]; ok {
+			panic("distinct keys encode identically, implement KeyEncoder for the key type")
+		}
+		encoded[string(*kvPairKey(&kv))] = struct{}{}
 
 		switch val := any(v).(type) {
 		case []byte:
@@ -121,51 +188,90 @@ func New[K comparable, V string | []byte | bool | uint64 | uint32 | uint16 | uin
 			} else {
 				*kvPairValue(&kv) = []byte{0}
 			}
+		case uint:
+			var b [8]byte
+			binary.BigEndian.PutUint64(b[:], uint64(val))
+			*kvPairValue(&kv) = b[8-((*bitLimit+7)/8):]
 		case uint64:
 			var b [8]byte
 			binary.BigEndian.PutUint64(b[:], uint64(val))
-			*kvPairValue(&kv) = b[8-((bitLimit+7)/8):]
+			*kvPairValue(&kv) = b[8-((*bitLimit+7)/8):]
 		case uint32:
 			var b [4]byte
 			binary.BigEndian.PutUint32(b[:], uint32(val))
-			*kvPairValue(&kv) = b[4-((bitLimit+7)/8):]
+			*kvPairValue(&kv) = b[4-((*bitLimit+7)/8):]
 		case uint16:
 			var b [2]byte
 			binary.BigEndian.PutUint16(b[:], uint16(val))
-			*kvPairValue(&kv) = b[2-((bitLimit+7)/8):]
+			*kvPairValue(&kv) = b[2-((*bitLimit+7)/8):]
 		case uint8:
 			var b = []byte{byte(val)}
 			*kvPairValue(&kv) = b[:]
+		case int:
+			*kvPairValue(&kv) = signedValue(int64(val), *bitLimit, 64)
+		case int64:
+			*kvPairValue(&kv) = signedValue(val, *bitLimit, 64)
+		case int32:
+			*kvPairValue(&kv) = signedValue(int64(val), *bitLimit, 32)
+		case int16:
+			*kvPairValue(&kv) = signedValue(int64(val), *bitLimit, 16)
+		case int8:
+			*kvPairValue(&kv) = signedValue(int64(val), *bitLimit, 8)
+		case float64:
+			*kvPairValue(&kv) = floatValue(val, *bitLimit, 64)
+		case float32:
+			*kvPairValue(&kv) = floatValue(float64(val), *bitLimit, 32)
 		default:
 			continue
 		}
-		pairs = append(pairs, kv)
+		ret = append(ret, kv)
 	}
+	return ret
+}
 
-	// handle the empty pairs case (all values were empty)
-	if len(pairs) == 0 {
-		return []byte{bloomFuncs, bitLimit}
+// signedValue encodes num sign-extended to bitLimit bits, or to width bits when Unlimited
+func signedValue(num int64, bitLimit, width byte) []byte {
+	if bitLimit == 0 {
+		bitLimit = width
 	}
-
-	// Create iterator over the materialized pairs
-	iter := func(yield func(kvPair [2][]byte) bool) {
-		for i := range pairs {
-			if !yield(pairs[i]) {
-				return
-			}
-		}
+	if bitLimit > 64 {
+		panic("bit limit exceeds 64 bits for integer value")
+	}
+	if signExtend(uint64(num), uint64(bitLimit)) != num {
+		panic("value doesn't fit in bit limit")
 	}
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], uint64(num))
+	return b[8-((bitLimit+7)/8):]
+}
 
-	// real impl
-	return create(iter, bitLimit, bloomFuncs)
+// floatValue encodes num as the leading bitLimit bits of a float32 when bitLimit is up to 32 bits,
+// or of a float64 otherwise. Unlimited stores width bits.
+func floatValue(num float64, bitLimit, width byte) []byte {
+	if bitLimit == 0 {
+		bitLimit = width
+	}
+	if bitLimit > 64 {
+		panic("bit limit exceeds 64 bits for float value")
+	}
+	var bits uint64
+	if bitLimit <= 32 {
+		bits = uint64(math.Float32bits(float32(num)) >> (32 - bitLimit))
+	} else {
+		bits = math.Float64bits(num) >> (64 - bitLimit)
+	}
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], bits)
+	return b[8-((bitLimit+7)/8):]
 }
 
 // Make generates the filter based on map m
-func Make[K comparable, V string | []byte | bool | uint64 | uint32 | uint16 | uint8](m map[K]V, bitLimit byte) []byte {
+func Make[K comparable, V Value](m map[K]V, bitLimit byte) []byte {
 	return New(m, bitLimit, 0)
 }
 
-// Bools retrieves a bool and the probabilistic membership based on comparable key
+// Bools retrieves a bool and the probabilistic membership based on comparable key.
+// The membership is only meaningful with bloomFuncs, use NewVerified for a fixed rate.
 func GetBools[K comparable](f []byte, key K) (bool, bool) {
 	k := comparableToBytes(key)
 	// real impl
@@ -213,3 +319,33 @@ func GetNum[K comparable](f []byte, valBitSize uint64, key K) uint64 {
 	return binary.BigEndian.Uint64(buf[:])
 
 }
+
+// GetInt retrieves a signed number based on comparable key and value bit size,
+// sign-extending the valBitSize bits stored for it
+func GetInt[K comparable](f []byte, valBitSize uint64, key K) int64 {
+	return signExtend(GetNum(f, valBitSize, key), valBitSize)
+}
+
+// signExtend extends the sign of the lowest valBitSize bits of num
+func signExtend(num uint64, valBitSize uint64) int64 {
+	shift := 64 - valBitSize
+	return int64(num<<shift) >> shift
+}
+
+// GetFloat retrieves a float based on comparable key and value bit size. Up to 32 bits
+// are the leading bits of a float32, more bits are the leading bits of a float64.
+// Filters created by NewQuantized or NewQuantizedLog are dequantized, ignoring valBitSize.
+func GetFloat[K comparable](f []byte, valBitSize uint64, key K) float64 {
+	if q, core, ok := quantizedOf(f); ok {
+		return getQuantized(q, core, key)
+	}
+	return floatOf(GetNum(f, valBitSize, key), valBitSize)
+}
+
+// floatOf decodes the lowest valBitSize bits of num encoded by floatValue
+func floatOf(num uint64, valBitSize uint64) float64 {
+	if valBitSize <= 32 {
+		return float64(math.Float32frombits(uint32(num) << (32 - valBitSize)))
+	}
+	return math.Float64frombits(num << (64 - valBitSize))
+}
@@ -427,3 +427,119 @@ func FuzzGetUnlimitedBehavior(t *testing.F) {
 		}
 	})
 }
+
+func TestInsertSigned(t *testing.T) {
+	for _, bits := range []byte{5, 8, 12, 16, Unlimited} {
+		m8 := make(map[int]int8)
+		m16 := make(map[int]int16)
+		m64 := make(map[int]int64)
+		for i := -15; i < 16; i++ {
+			m8[i] = int8(i)
+			m16[i] = int16(i * 3)
+			m64[i] = int64(i * 5)
+		}
+		valBits := uint64(bits)
+		f8, f16, f64 := Make(m8, bits), []byte(nil), []byte(nil)
+		if bits == Unlimited || bits >= 8 {
+			// i*5 needs 8 bits
+			f16, f64 = Make(m16, bits), Make(m64, bits)
+		}
+		for i := -15; i < 16; i++ {
+			want8, want16, want64 := int64(i), int64(i*3), int64(i*5)
+			if bits == Unlimited {
+				if got := GetInt(f8, 8, i); got != want8 {
+					t.Fatalf("unlimited int8 %d: got %d", i, got)
+				}
+				if got := GetInt(f16, 16, i); got != want16 {
+					t.Fatalf("unlimited int16 %d: got %d", i, got)
+				}
+				if got := GetInt(f64, 64, i); got != want64 {
+					t.Fatalf("unlimited int64 %d: got %d", i, got)
+				}
+				continue
+			}
+			if got := GetInt(f8, valBits, i); got != want8 {
+				t.Fatalf("bits %d int8 %d: got %d", bits, i, got)
+			}
+			if bits >= 8 {
+				if got := GetInt(f16, valBits, i); got != want16 {
+					t.Fatalf("bits %d int16 %d: got %d", bits, i, got)
+				}
+				if got := GetInt(f64, valBits, i); got != want64 {
+					t.Fatalf("bits %d int64 %d: got %d", bits, i, got)
+				}
+			}
+		}
+	}
+
+	mi, mu := make(map[int]int), make(map[int]uint)
+	for i := -1000; i < 1000; i++ {
+		mi[i], mu[i] = i*1000, uint(i+1000)
+	}
+	fi, fu := Make(mi, 22), Make(mu, 11)
+	for i := -1000; i < 1000; i++ {
+		if got := GetInt(fi, 22, i); got != int64(i*1000) {
+			t.Fatalf("int %d: got %d", i, got)
+		}
+		if got := GetNum(fu, 11, i); got != uint64(i+1000) {
+			t.Fatalf("uint %d: got %d", i, got)
+		}
+	}
+	if got := NewMap(mi, Options{}).Get(-7); got != -7000 {
+		t.Fatalf("Map int: got %d", got)
+	}
+}
+
+func TestValueExceedsBitLimit(t *testing.T) {
+	for name, build := range map[string]func(){
+		"int8":  func() { Make(map[int]int8{1: 16}, 5) },
+		"int8-": func() { Make(map[int]int8{1: -17}, 5) },
+		"int":   func() { Make(map[int]int{1: 1 << 40}, 32) },
+	} {
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Fatalf("%s: expected panic for a value exceeding the bit limit", name)
+				}
+			}()
+			build()
+		}()
+	}
+
+	// unsigned values keep their low bits
+	if got := GetNum(Make(map[int]uint16{1: 0x1ff}, 8), 8, 1); got != 0xff {
+		t.Fatalf("uint16 truncated to %x want ff", got)
+	}
+	if got := GetNum(Make(map[int]uint{1: 0x1ff}, 8), 8, 1); got != 0xff {
+		t.Fatalf("uint truncated to %x want ff", got)
+	}
+}
+
+func TestInsertFloats(t *testing.T) {
+	m32 := map[string]float32{"a": 1.5, "b": -273.15, "c": 0, "d": 3.4e38}
+	m64 := map[string]float64{"a": 1.5, "b": -273.15, "c": 1e-300, "d": 6.02214076e23}
+
+	f32 := Make(m32, 32)
+	for k, v := range m32 {
+		if got := GetFloat(f32, 32, k); got != float64(v) {
+			t.Fatalf("float32 %s: got %v want %v", k, got, v)
+		}
+	}
+	f64 := Make(m64, Unlimited)
+	for k, v := range m64 {
+		if got := GetFloat(f64, 64, k); got != v {
+			t.Fatalf("float64 %s: got %v want %v", k, got, v)
+		}
+	}
+	// 16 bits keep the sign, exponent and 7 mantissa bits of a float32
+	f16 := Make(m64, 16)
+	for _, k := range []string{"a", "b", "d"} {
+		got, want := GetFloat(f16, 16, k), m64[k]
+		if got/want < 0.99 || got/want > 1.01 {
+			t.Fatalf("float 16 bits %s: got %v want about %v", k, got, want)
+		}
+	}
+	if got := GetFloat(f16, 16, "a"); got != 1.5 {
+		t.Fatalf("float 16 bits a: got %v want 1.5", got)
+	}
+}
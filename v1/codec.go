@@ -0,0 +1,58 @@
+package v1
+
+import "encoding"
+
+// Codec converts values of type V to bytes and back, for storing values which aren't a Value
+type Codec[V any] interface {
+	Encode(value V) ([]byte, error)
+	Decode(data []byte) (V, error)
+}
+
+// BinaryCodec is the Codec of types implementing encoding.BinaryMarshaler and
+// encoding.BinaryUnmarshaler by pointer, such as BinaryCodec[time.Time, *time.Time]{}
+type BinaryCodec[V any, P interface {
+	*V
+	encoding.BinaryMarshaler
+	encoding.BinaryUnmarshaler
+}] struct{}
+
+// Encode marshals value by its MarshalBinary method
+func (BinaryCodec[V, P]) Encode(value V) ([]byte, error) {
+	return P(&value).MarshalBinary()
+}
+
+// Decode unmarshals data by the UnmarshalBinary method
+func (BinaryCodec[V, P]) Decode(data []byte) (value V, err error) {
+	err = P(&value).UnmarshalBinary(data)
+	return
+}
+
+// NewCodec generates the map based on map m, storing the values encoded by codec
+// as variable length values, see NewVarLen. It panics if a value fails to encode.
+func NewCodec[K comparable, V any](m map[K]V, codec Codec[V], bloomFuncs byte) []byte {
+	encoded := make(map[K][]byte, len(m))
+	for k, v := range m {
+		data, err := codec.Encode(v)
+		if err != nil {
+			panic(err)
+		}
+		encoded[k] = data
+	}
+	return NewVarLen(encoded, bloomFuncs)
+}
+
+// GetCodec retrieves the value based on comparable key from a filter created by NewCodec,
+// decoding it by codec. ok is false if the key surely wasn't inserted or the stored bytes
+// fail to decode, which happens for some absent keys.
+func GetCodec[K comparable, V any](f []byte, codec Codec[V], key K) (value V, ok bool) {
+	data := getVarLen(f, comparableToBytes(key))
+	if data == nil {
+		return value, false
+	}
+	value, err := codec.Decode(data)
+	if err != nil {
+		var zero V
+		return zero, false
+	}
+	return value, true
+}
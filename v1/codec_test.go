@@ -0,0 +1,112 @@
+package v1
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// point is a fixed size record
+type point struct {
+	X, Y int32
+}
+
+func (p point) MarshalBinary() ([]byte, error) {
+	var b [8]byte
+	binary.BigEndian.PutUint32(b[:], uint32(p.X))
+	binary.BigEndian.PutUint32(b[4:], uint32(p.Y))
+	return b[:], nil
+}
+
+func (p *point) UnmarshalBinary(data []byte) error {
+	if len(data) != 8 {
+		return errors.New("point must be 8 bytes")
+	}
+	p.X = int32(binary.BigEndian.Uint32(data))
+	p.Y = int32(binary.BigEndian.Uint32(data[4:]))
+	return nil
+}
+
+// record is a variable size record
+type record struct {
+	Name string
+	Tags []string
+}
+
+// jsonCodec is a Codec of any type using encoding/json
+type jsonCodec[V any] struct{}
+
+func (jsonCodec[V]) Encode(value V) ([]byte, error) {
+	return json.Marshal(value)
+}
+
+func (jsonCodec[V]) Decode(data []byte) (value V, err error) {
+	err = json.Unmarshal(data, &value)
+	return
+}
+
+func TestCodecFixedSize(t *testing.T) {
+	m := make(map[string]point)
+	for i := 0; i < 1000; i++ {
+		m[fmt.Sprint("p", i)] = point{int32(i), int32(-i * 3)}
+	}
+	codec := BinaryCodec[point, *point]{}
+	f := NewCodec[string, point](m, codec, 0)
+	for k, v := range m {
+		if got, ok := GetCodec(f, Codec[point](codec), k); !ok || got != v {
+			t.Fatalf("GetCodec(%s) = %v, %v want %v", k, got, ok, v)
+		}
+	}
+}
+
+func TestCodecVariableSize(t *testing.T) {
+	m := make(map[int]record)
+	for i := 0; i < 500; i++ {
+		r := record{Name: fmt.Sprint("record", i)}
+		for j := 0; j < i%5; j++ {
+			r.Tags = append(r.Tags, fmt.Sprint("tag", j))
+		}
+		m[i] = r
+	}
+	f := NewCodec[int, record](m, jsonCodec[record]{}, 0)
+	for k, v := range m {
+		got, ok := GetCodec[int, record](f, jsonCodec[record]{}, k)
+		if !ok || got.Name != v.Name || fmt.Sprint(got.Tags) != fmt.Sprint(v.Tags) {
+			t.Fatalf("GetCodec(%d) = %+v, %v want %+v", k, got, ok, v)
+		}
+	}
+
+	times := map[string]time.Time{"epoch": time.Unix(0, 0).UTC(), "now": time.Date(2024, 5, 6, 7, 8, 9, 10, time.UTC)}
+	ft := NewCodec[string, time.Time](times, BinaryCodec[time.Time, *time.Time]{}, 0)
+	for k, v := range times {
+		if got, ok := GetCodec[string, time.Time](ft, BinaryCodec[time.Time, *time.Time]{}, k); !ok || !got.Equal(v) {
+			t.Fatalf("GetCodec(%s) = %v, %v want %v", k, got, ok, v)
+		}
+	}
+}
+
+// broken fails to marshal
+type broken struct{}
+
+func (broken) MarshalBinary() ([]byte, error) {
+	return nil, errors.New("broken")
+}
+
+func (*broken) UnmarshalBinary([]byte) error {
+	return nil
+}
+
+func TestBinaryCodecError(t *testing.T) {
+	if _, err := (BinaryCodec[broken, *broken]{}).Encode(broken{}); err == nil {
+		t.Fatalf("Encode must return the MarshalBinary error")
+	}
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatalf("expected NewCodec to panic on the encoding error")
+		}
+	}()
+	NewCodec[int, broken](map[int]broken{1: {}}, BinaryCodec[broken, *broken]{}, 0)
+}
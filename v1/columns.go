@@ -0,0 +1,163 @@
+package v1
+
+import (
+	"encoding/binary"
+
+	sha256 "github.com/minio/sha256-simd"
+)
+
+// Column describes a field of the records stored by NewColumns
+type Column struct {
+	// Name identifies the column in GetField
+	Name string
+	// Bits is the bit size of the field values, 1 for a bool
+	Bits byte
+}
+
+// columnSection is a column of the schema together with its filter
+type columnSection struct {
+	Column
+	core []byte
+}
+
+// NewColumns generates the map based on map m of records, holding the field values in
+// the order of schema. Every column is stored as its own bit limited filter, and all of
+// the filters place the keys by the same key hash, so GetField reads only one column.
+func NewColumns[K comparable](m map[K][]uint64, schema []Column) []byte {
+	digests := make(map[K][]byte, len(m))
+	for k, fields := range m {
+		if len(fields) != len(schema) {
+			panic("record field count differs from the schema")
+		}
+		datb := sha256.Sum256(comparableToBytes(k))
+		digests[k] = datb[:]
+	}
+	var sections []columnSection
+	for i, col := range schema {
+		values := make(map[K]uint64, len(m))
+		for k, fields := range m {
+			values[k] = fields[i]
+		}
+		sections = append(sections, columnSection{col, newColumn(values, digests, col)})
+	}
+	return encodeColumns(sections)
+}
+
+// AddColumn returns the filter f created by NewColumns extended by column col
+// holding the values of map m. The existing columns are copied unchanged.
+func AddColumn[K comparable](f []byte, m map[K]uint64, col Column) []byte {
+	digests := make(map[K][]byte, len(m))
+	for k := range m {
+		datb := sha256.Sum256(comparableToBytes(k))
+		digests[k] = datb[:]
+	}
+	sections := decodeColumns(f)
+	return encodeColumns(append(sections, columnSection{col, newColumn(m, digests, col)}))
+}
+
+// newColumn creates the filter of a single column placing the keys by their digests
+func newColumn[K comparable](values map[K]uint64, digests map[K][]byte, col Column) []byte {
+	if col.Bits == 0 || col.Bits > 64 {
+		panic("column must be 1 to 64 bits wide")
+	}
+	bitLimit := col.Bits
+	pairs := materializeKeys(values, &bitLimit, func(key K) []byte {
+		return digests[key]
+	})
+	if len(pairs) == 0 {
+		return []byte{0, bitLimit}
+	}
+	return createDigest(pairs.iter, bitLimit, 0, 0, prehashed)
+}
+
+// encodeColumns wraps the column filters together with the schema,
+// the schema holds the name, the bit size and the filter size of every column
+func encodeColumns(sections []columnSection) []byte {
+	var n [binary.MaxVarintLen64]byte
+	meta := append([]byte(nil), n[:binary.PutUvarint(n[:], uint64(len(sections)))]...)
+	var core []byte
+	names := make(map[string]struct{}, len(sections))
+	for _, s := range sections {
+		if _, ok := names[s.Name]; ok {
+			panic("duplicate column name")
+		}
+		names[s.Name] = struct{}{}
+		meta = append(meta, n[:binary.PutUvarint(n[:], uint64(len(s.Name)))]...)
+		meta = append(meta, s.Name...)
+		meta = append(meta, s.Bits)
+		meta = append(meta, n[:binary.PutUvarint(n[:], uint64(len(s.core)))]...)
+		core = append(core, s.core...)
+	}
+	return wrap(kindColumns, meta, core)
+}
+
+// eachColumn calls yield with every column of a filter created by NewColumns,
+// until yield returns false
+func eachColumn(f []byte, yield func(name []byte, bits byte, core []byte) bool) {
+	meta, core := unwrap(f, kindColumns)
+	count, l := binary.Uvarint(meta)
+	if l <= 0 {
+		panic("filter metadata truncated")
+	}
+	meta = meta[l:]
+	for i := uint64(0); i < count; i++ {
+		nameLen, l := binary.Uvarint(meta)
+		if l <= 0 || uint64(len(meta)-l) <= nameLen {
+			panic("filter metadata truncated")
+		}
+		name := meta[l : l+int(nameLen)]
+		meta = meta[l+int(nameLen):]
+		bits := meta[0]
+		size, l := binary.Uvarint(meta[1:])
+		if l <= 0 || size > uint64(len(core)) {
+			panic("filter metadata truncated")
+		}
+		meta = meta[1+l:]
+		if !yield(name, bits, core[:size]) {
+			return
+		}
+		core = core[size:]
+	}
+}
+
+// decodeColumns splits a filter created by NewColumns into the column filters
+func decodeColumns(f []byte) (sections []columnSection) {
+	eachColumn(f, func(name []byte, bits byte, core []byte) bool {
+		sections = append(sections, columnSection{Column{string(name), bits}, core})
+		return true
+	})
+	return
+}
+
+// Schema returns the columns of a filter created by NewColumns
+func Schema(f []byte) (schema []Column) {
+	for _, s := range decodeColumns(f) {
+		schema = append(schema, s.Column)
+	}
+	return
+}
+
+// GetField retrieves the value of column name based on comparable key from a filter
+// created by NewColumns, reading only the cells of that column. It panics if there is no such column.
+func GetField[K comparable](f []byte, key K, name string) (value uint64) {
+	var found bool
+	eachColumn(f, func(col []byte, bits byte, core []byte) bool {
+		if string(col) != name {
+			return true
+		}
+		found = true
+		if len(core) <= 2 {
+			return false
+		}
+		datb := sha256.Sum256(comparableToBytes(key))
+		var buf [8]byte
+		b := getHashed(core, &datb, uint64(bits), 0)
+		copy(buf[8-len(b):], b)
+		value = binary.BigEndian.Uint64(buf[:])
+		return false
+	})
+	if !found {
+		panic("no such column")
+	}
+	return
+}
@@ -0,0 +1,46 @@
+package v1
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestColumns(t *testing.T) {
+	schema := []Column{{"status", 3}, {"region", 6}, {"score", 10}, {"flag", 1}}
+	m := make(map[string][]uint64)
+	for i := 0; i < 1000; i++ {
+		m[fmt.Sprint("user", i)] = []uint64{uint64(i % 8), uint64(i % 61), uint64(i), uint64(i % 2)}
+	}
+	f := NewColumns(m, schema)
+	for k, fields := range m {
+		for i, col := range schema {
+			if got := GetField(f, k, col.Name); got != fields[i] {
+				t.Fatalf("GetField(%s, %s) = %d want %d", k, col.Name, got, fields[i])
+			}
+		}
+	}
+
+	extra := make(map[string]uint64)
+	for k, fields := range m {
+		extra[k] = fields[2] * 3
+	}
+	g := AddColumn(f, extra, Column{"triple", 12})
+	if got := Schema(g); len(got) != 5 || got[4] != (Column{"triple", 12}) || got[2] != schema[2] {
+		t.Fatalf("Schema = %v", got)
+	}
+	for k, fields := range m {
+		if got := GetField(g, k, "triple"); got != extra[k] {
+			t.Fatalf("GetField(%s, triple) = %d want %d", k, got, extra[k])
+		}
+		if got := GetField(g, k, "score"); got != fields[2] {
+			t.Fatalf("GetField(%s, score) = %d want %d after AddColumn", k, got, fields[2])
+		}
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatalf("expected panic for a missing column")
+		}
+	}()
+	GetField(f, "user1", "missing")
+}
@@ -1,5 +1,7 @@
 package v1
 
+import sha256 "github.com/minio/sha256-simd"
+
 func byteSize(n uint64) uint64 {
 	return (3 + n) / 4
 }
@@ -28,6 +30,22 @@ func kvPairValue(kvPair *[2][]byte) *[]byte {
 type Iterator func(yield func(kvPair [2][]byte) bool)
 
 func create(iter Iterator, bitLimit, bloomFuncs byte) (filter []byte) {
+	return createSized(iter, bitLimit, bloomFuncs, 0)
+}
+
+// createSized is create which starts with at least minBytes of cells
+func createSized(iter Iterator, bitLimit, bloomFuncs byte, minBytes uint64) (filter []byte) {
+	return createDigest(iter, bitLimit, bloomFuncs, minBytes, sha256.Sum256)
+}
+
+// prehashed is the key digest of keys which are already 32 byte digests
+func prehashed(key []byte) (datb [32]byte) {
+	copy(datb[:], key)
+	return
+}
+
+// createDigest is createSized which hashes the keys using digest
+func createDigest(iter Iterator, bitLimit, bloomFuncs byte, minBytes uint64, digest func([]byte) [32]byte) (filter []byte) {
 	var size uint64
 	var maxb uint64
 	if bitLimit == 1 {
@@ -57,10 +75,14 @@ func create(iter Iterator, bitLimit, bloomFuncs byte) (filter []byte) {
 		})
 	}
 	bytes := byteSize(grow(size))
+	var maxLoad = size
+	if bytes < minBytes {
+		bytes = minBytes
+		maxLoad = bitSize(bytes)
+	}
 	filter = make([]byte, bytes+2, bytes+2)
 	filter[bytes+1] = bitLimit
 	filter[bytes] = bloomFuncs
-	var maxLoad = size
 	for {
 		// BLOOM STAGE
 		var is_mutated = bloomFuncs > 0
@@ -68,7 +90,8 @@ func create(iter Iterator, bitLimit, bloomFuncs byte) (filter []byte) {
 		for is_mutated && load < maxLoad {
 			var bloom_inserted uint64
 			iter(func(kv [2][]byte) bool {
-				ins := put(filter, *kvPairKey(&kv), bloomFuncs)
+				datb := digest(*kvPairKey(&kv))
+				ins := put(filter, &datb, bloomFuncs)
 				bloom_inserted += uint64(ins)
 				if load+bloom_inserted >= maxLoad {
 					return false
@@ -103,7 +126,8 @@ func create(iter Iterator, bitLimit, bloomFuncs byte) (filter []byte) {
 				if 8*len(*kvPairValue(&kv)) < 256 && byte(8*len(*kvPairValue(&kv))) < stored {
 					stored = byte(8 * len(*kvPairValue(&kv)))
 				}
-				ins := store(filter, *kvPairKey(&kv), *kvPairValue(&kv), stored)
+				datb := digest(*kvPairKey(&kv))
+				ins := store(filter, &datb, *kvPairValue(&kv), stored)
 				new_inserted += ins
 				if load+new_inserted >= maxLoad {
 					return false
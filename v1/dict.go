@@ -0,0 +1,127 @@
+package v1
+
+import (
+	"encoding/binary"
+	"math/bits"
+	"sort"
+)
+
+// NewDict generates the map based on map m holding few distinct values.
+// Every distinct value is stored once in a dictionary table, the quaternary filter stores
+// a ceil(log2(distinct)) bit code per key. It suits values such as country codes or categories.
+func NewDict[K comparable, V string | []byte](m map[K]V, bloomFuncs byte) []byte {
+	distinct := make(map[string]struct{})
+	for _, v := range m {
+		distinct[string(v)] = struct{}{}
+	}
+	table, codes, codeBits := dictionary(distinct)
+
+	keys := make(map[K]uint64, len(m))
+	for k, v := range m {
+		keys[k] = codes[string(v)]
+	}
+	return wrap(kindDict, append([]byte(dictMagic), table...), New(keys, codeBits, bloomFuncs))
+}
+
+// dictMagic opens the metadata of NewDict filters, so that filters of other kinds are told apart
+const dictMagic = "\xffdct"
+
+// dictOf returns the dictionary table and the core filter of a filter created by NewDict,
+// ok is false for any other filter
+func dictOf(f []byte) (table []byte, core []byte, ok bool) {
+	meta, core, ok := unwrapOK(f, kindDict)
+	if !ok || len(meta) < len(dictMagic)+8 || string(meta[:len(dictMagic)]) != dictMagic || len(core) < 2 {
+		return nil, nil, false
+	}
+	table = meta[len(dictMagic):]
+	if uint64(len(table)) < 8+4*uint64(binary.LittleEndian.Uint32(table)) {
+		return nil, nil, false
+	}
+	return table, core, true
+}
+
+// dictionary builds the table of the distinct values sorted, the code of every value
+// and the bit size of the codes
+func dictionary(distinct map[string]struct{}) (table []byte, codes map[string]uint64, codeBits byte) {
+	values := make([]string, 0, len(distinct))
+	for v := range distinct {
+		values = append(values, v)
+	}
+	sort.Strings(values)
+
+	// the table is the value count, the offsets of the values ending with the total size, and the values
+	codes = make(map[string]uint64, len(values))
+	table = make([]byte, 4+4*len(values)+4)
+	binary.LittleEndian.PutUint32(table, uint32(len(values)))
+	for i, v := range values {
+		codes[v] = uint64(i)
+		table = append(table, v...)
+		binary.LittleEndian.PutUint32(table[4+4*i+4:], uint32(len(table)-(4+4*len(values)+4)))
+	}
+
+	codeBits = 1
+	if len(values) > 1 {
+		codeBits = byte(bits.Len(uint(len(values) - 1)))
+	}
+	return
+}
+
+// dictValue returns value number code of the dictionary table, nil if there is none
+func dictValue(table []byte, code uint64) []byte {
+	count := uint64(binary.LittleEndian.Uint32(table))
+	if code >= count {
+		return nil
+	}
+	values := table[4+4*count+4:]
+	start := binary.LittleEndian.Uint32(table[4+4*code:])
+	end := binary.LittleEndian.Uint32(table[4+4*code+4:])
+	return values[start:end:end]
+}
+
+// getDict retrieves the value of the encoded key k from a filter created by NewDict,
+// the value is a slice of the dictionary table
+func getDict(f []byte, k []byte) []byte {
+	table, core, ok := dictOf(f)
+	if !ok {
+		panic("filter is not of the requested kind")
+	}
+	if len(core) <= 2 {
+		return nil
+	}
+	var buf [8]byte
+	b := get(core, k, uint64(core[len(core)-1]), core[len(core)-2])
+	if b == nil {
+		return nil
+	}
+	copy(buf[8-len(b):], b)
+	return dictValue(table, binary.BigEndian.Uint64(buf[:]))
+}
+
+// DictStats describes the layout of a filter created by NewDict
+type DictStats struct {
+	// Size is the total size of the filter in bytes
+	Size int
+	// FilterSize is the size of the quaternary filter holding the codes in bytes
+	FilterSize int
+	// CodeBits is the bit size of the code stored per key
+	CodeBits byte
+	// DictionaryValues is the number of distinct values
+	DictionaryValues int
+	// DictionarySize is the size of the dictionary table in bytes
+	DictionarySize int
+}
+
+// StatsDict returns the layout of a filter created by NewDict, ok is false for other filters
+func StatsDict(f []byte) (stats DictStats, ok bool) {
+	table, core, ok := dictOf(f)
+	if !ok {
+		return stats, false
+	}
+	return DictStats{
+		Size:             len(f),
+		FilterSize:       len(core),
+		CodeBits:         core[len(core)-1],
+		DictionaryValues: int(binary.LittleEndian.Uint32(table)),
+		DictionarySize:   len(table),
+	}, true
+}
@@ -0,0 +1,54 @@
+package v1
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestDict(t *testing.T) {
+	countries := []string{"CZ", "SK", "DE", "AT", "PL", "HU", "United Kingdom of Great Britain"}
+	m := make(map[string]string)
+	for i := 0; i < 10000; i++ {
+		m[fmt.Sprint("user", i)] = countries[i%len(countries)]
+	}
+	f := NewDict(m, 0)
+	for k, v := range m {
+		if got := GetStringValue(f, k); got != v {
+			t.Fatalf("GetStringValue(%s) = %q want %q", k, got, v)
+		}
+	}
+
+	stats, ok := StatsDict(f)
+	if !ok || stats.DictionaryValues != len(countries) || stats.CodeBits != 3 {
+		t.Fatalf("Stats = %+v want %d values of 3 bits", stats, len(countries))
+	}
+	if stats.DictionarySize != 4+4*(len(countries)+1)+len("CZSKDEATPLHUUnited Kingdom of Great Britain") {
+		t.Fatalf("Stats = %+v has a wrong dictionary size", stats)
+	}
+	if stats.Size != len(f) || stats.FilterSize+stats.DictionarySize >= stats.Size {
+		t.Fatalf("Stats = %+v inconsistent with size %d", stats, len(f))
+	}
+	if varlen := NewVarLen(m, 0); len(f)*3 > len(varlen) {
+		t.Fatalf("dictionary filter takes %d bytes, variable length filter %d bytes", len(f), len(varlen))
+	}
+
+	one := NewDict(map[int][]byte{1: []byte("x"), 2: []byte("x")}, 0)
+	if stats, _ := StatsDict(one); stats.CodeBits != 1 || GetStringValue(one, 2) != "x" {
+		t.Fatalf("single value dictionary returned %q", GetStringValue(one, 2))
+	}
+	if empty := NewDict(map[int]string{}, 0); GetStringValue(empty, 1) != "" {
+		t.Fatalf("empty dictionary returned a value")
+	}
+	if _, ok := StatsDict(Make(m, 0)); ok {
+		t.Fatalf("StatsDict accepted a filter not created by NewDict")
+	}
+	if _, ok := StatsDict(nil); ok {
+		t.Fatalf("StatsDict accepted an empty filter")
+	}
+	// plain filters whose first cell byte equals the kind
+	for _, raw := range [][]byte{{kindDict, 200, 1, 2}, {kindDict, 3, 1, 2, 3, 0, 8}, {kindDict}} {
+		if _, ok := StatsDict(raw); ok {
+			t.Fatalf("StatsDict accepted %x", raw)
+		}
+	}
+}
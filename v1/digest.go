@@ -0,0 +1,65 @@
+package v1
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+// Digest is a key which already is a uniformly distributed hash, such as a SHA-256 sum
+// or a 128-bit content address. Digest keys are used directly, without key encoding and hashing.
+type Digest interface {
+	[16]byte | [32]byte
+}
+
+// digestOf widens a digest key to the 32 bytes the cell positions are taken from
+func digestOf[D Digest](key D) (datb [32]byte) {
+	switch k := any(&key).(type) {
+	case *[32]byte:
+		return *k
+	case *[16]byte:
+		copy(datb[:], k[:])
+		a := binary.BigEndian.Uint64(k[:8])
+		b := binary.BigEndian.Uint64(k[8:])
+		binary.BigEndian.PutUint64(datb[16:], mix64(a^bits.RotateLeft64(b, 32)))
+		binary.BigEndian.PutUint64(datb[24:], mix64(b+0x9e3779b97f4a7c15))
+	}
+	return
+}
+
+// NewDigest generates the map based on map m keyed by digests, with garbage rate dependent on bloomFuncs.
+// The filter must be queried by GetDigest, GetBoolDigest or GetNumDigest with the same digest type.
+func NewDigest[D Digest, V Value](m map[D]V, bitLimit, bloomFuncs byte) []byte {
+	pairs := materializeKeys(m, &bitLimit, func(key D) []byte {
+		datb := digestOf(key)
+		return datb[:]
+	})
+
+	if len(pairs) == 0 {
+		return []byte{bloomFuncs, bitLimit}
+	}
+
+	return createDigest(pairs.iter, bitLimit, bloomFuncs, 0, prehashed)
+}
+
+// GetDigest retrieves an item based on digest key and value bit size
+func GetDigest[D Digest](f []byte, valBitSize uint64, key D) []byte {
+	datb := digestOf(key)
+	return getHashed(f, &datb, valBitSize, f[len(f)-2])
+}
+
+// GetBoolDigest retrieves a bool based on digest key (no allocations)
+func GetBoolDigest[D Digest](f []byte, key D) bool {
+	var ret [1]byte
+	var done [1]byte
+	datb := digestOf(key)
+	ok := getIntoHashed(f, &datb, ret[:], done[:], 1, f[len(f)-2])
+	return ok && (ret[0]&1 == 1)
+}
+
+// GetNumDigest retrieves a number based on digest key and value bit size
+func GetNumDigest[D Digest](f []byte, valBitSize uint64, key D) uint64 {
+	var buf [8]byte
+	b := GetDigest(f, valBitSize, key)
+	copy(buf[8-len(b):8], b)
+	return binary.BigEndian.Uint64(buf[:])
+}
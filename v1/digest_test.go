@@ -0,0 +1,45 @@
+package v1
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"testing"
+)
+
+func TestDigest(t *testing.T) {
+	m := make(map[[32]byte]uint16)
+	for i := 0; i < 1000; i++ {
+		m[sha256.Sum256([]byte(fmt.Sprint("key", i)))] = uint16(i * 7)
+	}
+	f := NewDigest(m, 16, 0)
+	for k, v := range m {
+		if got := uint16(GetNumDigest(f, 16, k)); got != v {
+			t.Fatalf("GetNumDigest(%x) = %d want %d", k, got, v)
+		}
+	}
+
+	b := NewDigest(map[[32]byte]bool{{1}: true, {2}: false, {3}: true}, 1, 2)
+	if !GetBoolDigest(b, [32]byte{1}) || GetBoolDigest(b, [32]byte{2}) || !GetBoolDigest(b, [32]byte{3}) {
+		t.Fatalf("GetBoolDigest returned wrong values")
+	}
+	if allocs := testing.AllocsPerRun(100, func() { GetBoolDigest(b, [32]byte{1}) }); allocs != 0 {
+		t.Fatalf("GetBoolDigest allocates %v times", allocs)
+	}
+}
+
+func TestDigest16(t *testing.T) {
+	m := make(map[[16]byte][]byte)
+	for i := 0; i < 500; i++ {
+		var k [16]byte
+		sum := sha256.Sum256([]byte(fmt.Sprint("key", i)))
+		copy(k[:], sum[:])
+		m[k] = []byte(fmt.Sprintf("val%04d", i))
+	}
+	f := NewDigest(m, 0, 0)
+	for k, v := range m {
+		if got := GetDigest(f, 8*uint64(len(v)), k); !bytes.Equal(got, v) {
+			t.Fatalf("GetDigest(%x) = %q want %q", k, got, v)
+		}
+	}
+}
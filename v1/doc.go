@@ -3,7 +3,7 @@
 //
 // This package extends the quaternary filter concept to support:
 //   - Any comparable key type (strings, integers, floats, custom types)
-//   - Variable-length values ([]byte, string, bool, uint8-uint64)
+//   - Variable-length values ([]byte, string, bool, uint, uint8-uint64, int, int8-int64, float32, float64)
 //   - Optional bloom filters to reduce false positive lookups
 //   - Configurable bit limits for compact value storage
 //
@@ -38,13 +38,27 @@
 // Any comparable type can be used as a key. Keys are converted to strings internally
 // using a deterministic encoding that preserves uniqueness.
 //
+// Types with an awkward default encoding can implement KeyEncoder, or register an
+// encoder with RegisterKeyEncoder, such as the MarshalBinary form of netip.Addr.
+// Creation panics if two distinct keys encode identically, and for pointer keys
+// without an encoder, as those would be encoded by their pointees. MakeComparable
+// of the root package rejects keys holding pointers as well.
+//
+// Keys which already are SHA-256 sums or 128-bit hashes are used as is by NewDigest
+// and GetDigest, skipping both the key encoding and the SHA-256 hashing.
+//
 // # Value Types
 //
 // Supported value types:
 //   - bool: Single bit storage
-//   - uint8, uint16, uint32, uint64: Compact integer storage
+//   - uint, uint8, uint16, uint32, uint64: Compact integer storage
+//   - int, int8, int16, int32, int64: Sign-extended to bitLimit bits, read by GetInt
+//   - float32, float64: Leading bitLimit bits, read by GetFloat
 //   - []byte, string: Variable-length data
 //
+// Signed integers which don't fit in bitLimit bits make creation panic, unsigned integers
+// keep their low bitLimit bits. With Unlimited, int and uint take 64 bits.
+//
 // # Bit Limits
 //
 // The bitLimit parameter controls value storage size:
@@ -62,6 +76,88 @@
 // bloomFuncs=0 disables bloom filtering. Higher values (1-8) provide better
 // false positive reduction at the cost of larger filter size.
 //
+// To target a garbage rate instead of picking bloomFuncs by hand:
+//
+//	filter := v1.NewWithFPR(m, 0.01) // about 1% of absent keys pass the bloom stage
+//	size := v1.EstimateSize(uint64(len(m)), 1, 0.01) // planned size in bytes
+//
+// # Fingerprints
+//
+// Alternatively a k-bit key fingerprint can be stored next to every value:
+//
+//	filter := v1.NewVerified(m, bitLimit, 0, 8)
+//	value, ok := v1.GetVerified(filter, valBitSize, key) // ok is wrong for 1/256 of absent keys
+//
+// The fingerprint gives an exact garbage rate of 2^-k and is usually smaller than
+// the bloom stage for the same rate.
+//
+// # Sparse Maps
+//
+// When almost every key carries a default value, only the exceptions need storing:
+//
+//	filter := v1.NewSparse(false, map[string]bool{"banned": true}, 1, 12)
+//	banned := v1.GetSparse[string, bool](filter, user)
+//
+// Keys which aren't exceptions return the default, except with rate 2^-fingerprintBits.
+//
+// # Variable Length Values
+//
+// Strings and byte slices of differing lengths are stored together with their lengths:
+//
+//	filter := v1.NewVarLen(map[string]string{"a": "apple", "b": "banana"}, 0)
+//	value := v1.GetStringValue(filter, "b") // returns "banana"
+//
+// NewHeap stores long values once in a heap appended to the filter, the cells hold
+// only the heap offsets. NewDict stores few distinct values once in a dictionary and
+// a short code per key, StatsDict reports the dictionary size. GetBytesValue returns heap
+// and dictionary values without copying.
+//
+// # Multimaps
+//
+// Keys mapping to lists of values are stored by NewMulti and read by GetAll:
+//
+//	filter := v1.NewMulti(map[string][]string{"a": {"x", "y"}}, 0)
+//	tags := v1.GetAll[string, string](filter, "a") // returns ["x" "y"]
+//
+// # Longest Prefix Match
+//
+// PrefixTable maps IP prefixes to values such as ASNs or countries:
+//
+//	table := v1.NewPrefixTable(map[netip.Prefix]uint32{prefix: 64512}, 0, 16)
+//	asn, ok := table.Lookup(addr) // value of the longest prefix containing addr
+//
+// # Columns
+//
+// Records of small fields are stored column by column, so a lookup reads only one column:
+//
+//	schema := []v1.Column{{Name: "status", Bits: 3}, {Name: "score", Bits: 10}}
+//	filter := v1.NewColumns(map[string][]uint64{"a": {5, 700}}, schema)
+//	score := v1.GetField(filter, "a", "score") // returns 700
+//
+// # Struct Values
+//
+// Values of any type can be stored by a Codec, BinaryCodec adapts encoding.BinaryMarshaler:
+//
+//	filter := v1.NewCodec(m, v1.BinaryCodec[Point, *Point]{}, 0)
+//	p, ok := v1.GetCodec[string, Point](filter, v1.BinaryCodec[Point, *Point]{}, key)
+//
+// # Typed Maps
+//
+// Map remembers the value type and width, so lookups need no bit size:
+//
+//	m := v1.NewMap(map[string]int16{"a": -5}, v1.Options{FingerprintBits: 8})
+//	value, ok := m.Lookup("a")       // returns -5, true
+//	m = v1.LoadMap[string, int16](m.Bytes())
+//
+// # Quantized Values
+//
+// Floats such as probabilities can be stored as short codes within a range:
+//
+//	filter := v1.NewQuantized(m, 8, 0, 1) // 8 bit codes between 0 and 1
+//	p := v1.GetFloat(filter, 8, key)      // error at most 1/255/2
+//
+// NewQuantizedLog spaces the codes logarithmically instead.
+//
 // # Implementation Details
 //
 // V1 uses a quaternary (4-state) cell encoding with SHA256-based hashing for
@@ -0,0 +1,51 @@
+package v1
+
+import "encoding/binary"
+
+// Kinds of filters which wrap a core filter together with kind specific metadata
+const (
+	kindVerified byte = 1 + iota
+	kindQuantized
+	kindVarLen
+	kindHeap
+	kindDict
+	kindMap
+	kindColumns
+	kindSparse
+	kindPrefix
+)
+
+// wrap prepends the kind and the length prefixed metadata to the core filter
+func wrap(kind byte, meta []byte, core []byte) []byte {
+	var n [binary.MaxVarintLen64]byte
+	l := binary.PutUvarint(n[:], uint64(len(meta)))
+	f := make([]byte, 0, 1+l+len(meta)+len(core))
+	f = append(f, kind)
+	f = append(f, n[:l]...)
+	f = append(f, meta...)
+	return append(f, core...)
+}
+
+// unwrap splits a filter created by wrap into the metadata and the core filter
+func unwrap(f []byte, kind byte) (meta []byte, core []byte) {
+	if len(f) == 0 || f[0] != kind {
+		panic("filter is not of the requested kind")
+	}
+	meta, core, ok := unwrapOK(f, kind)
+	if !ok {
+		panic("filter metadata truncated")
+	}
+	return meta, core
+}
+
+// unwrapOK is unwrap which returns false instead of panicking
+func unwrapOK(f []byte, kind byte) (meta []byte, core []byte, ok bool) {
+	if len(f) == 0 || f[0] != kind {
+		return nil, nil, false
+	}
+	n, l := binary.Uvarint(f[1:])
+	if l <= 0 || uint64(len(f)-1-l) < n {
+		return nil, nil, false
+	}
+	return f[1+l : 1+l+int(n)], f[1+l+int(n):], true
+}
@@ -0,0 +1,114 @@
+package v1
+
+import "math"
+
+// maxBloomFuncs is the number of distinct bloom functions put and get can evaluate
+const maxBloomFuncs = ROUNDS * (ROUNDS - 1)
+
+// quaternaryBitsPerValueBit is the measured average of new bits the quaternary stage
+// sets per stored value bit on top of a dense bloom stage, rounded up
+const quaternaryBitsPerValueBit = 1.25
+
+// planFPR picks the bloom function count and the cell byte size needed to keep the
+// garbage rate for absent keys at or below fpr.
+//
+// The bloom bits share the byte array with the quaternary cells, so the cells written
+// by the quaternary stage raise the bloom density too. The bloom stage leaves a density
+// of 1 - e^(-funcs*n/bits), the quaternary stage then sets about
+// quaternaryBitsPerValueBit previously clear bits per stored value bit, and the garbage
+// rate is the final density to the power of funcs.
+func planFPR(n, valueBits uint64, fpr float64) (funcs byte, bytes uint64) {
+	if n == 0 {
+		return 0, 0
+	}
+	if !(fpr > 0) {
+		panic("false positive rate must be positive")
+	}
+	least := byteSize(grow(n * valueBits))
+	if fpr >= 1 {
+		return 0, least
+	}
+	density := func(k int, bits float64) float64 {
+		return -math.Expm1(-float64(uint64(k)*n)/bits) +
+			quaternaryBitsPerValueBit*float64(n*valueBits)/bits
+	}
+	best := math.Inf(1)
+	for k := 1; k <= maxBloomFuncs; k++ {
+		target := math.Pow(fpr, 1/float64(k))
+		// the density falls as the size grows, find the smallest size by bisection
+		lo, hi := float64(1), float64(8)
+		for density(k, hi) > target {
+			lo, hi = hi, hi*2
+		}
+		for hi-lo > 1 {
+			mid := (lo + hi) / 2
+			if density(k, mid) > target {
+				lo = mid
+			} else {
+				hi = mid
+			}
+		}
+		if hi < best {
+			best = hi
+			funcs = byte(k)
+		}
+	}
+	bytes = uint64(math.Ceil(best / 8))
+	if bytes < least {
+		bytes = least
+	}
+	return funcs, bytes
+}
+
+// EstimateSize returns the expected filter size in bytes for n keys holding valueBits
+// bits each with garbage rate fpr for absent keys, as built by NewWithFPR
+func EstimateSize(n, valueBits uint64, fpr float64) uint64 {
+	if n == 0 || valueBits == 0 {
+		return 2
+	}
+	_, bytes := planFPR(n, valueBits, fpr)
+	return bytes + 2
+}
+
+// NewWithFPR generates the map based on map m, choosing the bloom function count and
+// the filter size so that lookups of absent keys report a miss except with rate fpr
+func NewWithFPR[K comparable, V Value](m map[K]V, fpr float64) []byte {
+	var bitLimit = nativeBitLimit[V]()
+	pairs := materialize(m, &bitLimit)
+
+	if len(pairs) == 0 {
+		return []byte{0, bitLimit}
+	}
+
+	var valueBits uint64
+	for _, kv := range pairs {
+		if bitLimit != 0 {
+			valueBits += uint64(bitLimit)
+		} else {
+			valueBits += uint64(len(*kvPairValue(&kv))) * 8
+		}
+	}
+	valueBits = (valueBits + uint64(len(pairs)) - 1) / uint64(len(pairs))
+
+	funcs, bytes := planFPR(uint64(len(pairs)), valueBits, fpr)
+
+	return createSized(pairs.iter, bitLimit, funcs, bytes)
+}
+
+// nativeBitLimit returns the bit limit matching the width of the value type
+func nativeBitLimit[V Value]() byte {
+	var v V
+	switch any(v).(type) {
+	case bool:
+		return 1
+	case uint8, int8:
+		return 8
+	case uint16, int16:
+		return 16
+	case uint32, int32, float32:
+		return 32
+	case uint, int, uint64, int64, float64:
+		return 64
+	}
+	return Unlimited
+}
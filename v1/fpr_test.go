@@ -0,0 +1,75 @@
+package v1
+
+import (
+	"testing"
+)
+
+func TestNewWithFPRAchievedRate(t *testing.T) {
+	const n = 2000
+	const heldOut = 50000
+
+	bools := make(map[uint64]bool)
+	nums := make(map[uint64]uint16)
+	for i := uint64(0); i < n; i++ {
+		bools[i] = i%3 == 0
+		nums[i] = uint16(i * 7)
+	}
+
+	for _, c := range []struct {
+		name      string
+		valueBits uint64
+		build     func(fpr float64) []byte
+		check     func(f []byte) (key uint64, ok bool)
+	}{
+		{"bool", 1, func(fpr float64) []byte { return NewWithFPR(bools, fpr) }, func(f []byte) (uint64, bool) {
+			for k, v := range bools {
+				if GetBool(f, k) != v {
+					return k, false
+				}
+			}
+			return 0, true
+		}},
+		{"uint16", 16, func(fpr float64) []byte { return NewWithFPR(nums, fpr) }, func(f []byte) (uint64, bool) {
+			for k, v := range nums {
+				if uint16(GetNum(f, 16, k)) != v {
+					return k, false
+				}
+			}
+			return 0, true
+		}},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			for _, fpr := range []float64{0.1, 0.01, 0.001} {
+				f := c.build(fpr)
+				if k, ok := c.check(f); !ok {
+					t.Fatalf("fpr=%v: key %d returned a wrong value", fpr, k)
+				}
+
+				var falsePositives int
+				for i := uint64(n); i < n+heldOut; i++ {
+					if Get(f, 1, i) != nil {
+						falsePositives++
+					}
+				}
+				rate := float64(falsePositives) / heldOut
+				if rate > 1.5*fpr {
+					t.Fatalf("fpr=%v: achieved rate %v on held-out keys", fpr, rate)
+				}
+				if est := EstimateSize(n, c.valueBits, fpr); uint64(len(f)) < est {
+					t.Fatalf("fpr=%v: size %d below estimate %d", fpr, len(f), est)
+				}
+			}
+		})
+	}
+}
+
+func TestEstimateSizeGrowsAsRateTightens(t *testing.T) {
+	prev := EstimateSize(1000, 1, 0.5)
+	for _, fpr := range []float64{0.1, 0.01, 0.001, 0.0001} {
+		size := EstimateSize(1000, 1, fpr)
+		if size <= prev {
+			t.Fatalf("size %d for rate %v not above %d", size, fpr, prev)
+		}
+		prev = size
+	}
+}
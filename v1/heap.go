@@ -0,0 +1,56 @@
+package v1
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+// NewHeap generates the map based on map m, storing the values out of line in a heap.
+// The quaternary filter stores only the heap offset of every value, equal values are
+// stored once. It suits large values such as documents or translations, where storing
+// the value bits in the cells would cost about 1.5x their size.
+func NewHeap[K comparable, V string | []byte](m map[K]V, bloomFuncs byte) []byte {
+	var heap []byte
+	var n [binary.MaxVarintLen64]byte
+	offsets := make(map[K]uint64, len(m))
+	stored := make(map[string]uint64)
+	for k, v := range m {
+		off, ok := stored[string(v)]
+		if !ok {
+			off = uint64(len(heap))
+			stored[string(v)] = off
+			l := binary.PutUvarint(n[:], uint64(len(v)))
+			heap = append(heap, n[:l]...)
+			heap = append(heap, v...)
+		}
+		offsets[k] = off
+	}
+	offBits := byte(bits.Len64(uint64(len(heap))))
+	return wrap(kindHeap, heap, New(offsets, offBits, bloomFuncs))
+}
+
+// getHeap retrieves the value of the encoded key k from a filter created by NewHeap,
+// the value is a slice of the heap
+func getHeap(f []byte, k []byte) []byte {
+	heap, core := unwrap(f, kindHeap)
+	if len(core) <= 2 {
+		return nil
+	}
+	var buf [8]byte
+	b := get(core, k, uint64(core[len(core)-1]), core[len(core)-2])
+	if b == nil {
+		return nil
+	}
+	copy(buf[8-len(b):], b)
+	off := binary.BigEndian.Uint64(buf[:])
+	if off >= uint64(len(heap)) {
+		return nil
+	}
+	n, l := binary.Uvarint(heap[off:])
+	if l <= 0 || n > uint64(len(heap))-off-uint64(l) {
+		// garbage offset of an absent key
+		return nil
+	}
+	start := off + uint64(l)
+	return heap[start : start+n : start+n]
+}
@@ -0,0 +1,48 @@
+package v1
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestHeap(t *testing.T) {
+	m := make(map[string]string)
+	for i := 0; i < 1000; i++ {
+		m[fmt.Sprint("doc", i)] = strings.Repeat(fmt.Sprint("word", i, " "), 20+i%7)
+	}
+	m["same1"] = "shared"
+	m["same2"] = "shared"
+	m["empty"] = ""
+	f := NewHeap(m, 0)
+	for k, v := range m {
+		if got := GetStringValue(f, k); got != v {
+			t.Fatalf("GetStringValue(%s) = %q want %q", k, got, v)
+		}
+	}
+
+	var total int
+	for _, v := range m {
+		total += len(v)
+	}
+	// about 2.5x the offset bits per key on top of the values
+	if len(f) > total+16*len(m) {
+		t.Fatalf("heap filter takes %d bytes for %d bytes of values", len(f), total)
+	}
+	if varlen := NewVarLen(m, 0); len(f)*2 > len(varlen) {
+		t.Fatalf("heap filter takes %d bytes, variable length filter %d bytes", len(f), len(varlen))
+	}
+
+	b := NewHeap(map[int][]byte{1: {1, 2, 3}, 2: {4}}, 0)
+	v := GetBytesValue(b, 1)
+	if !bytes.Equal(v, []byte{1, 2, 3}) {
+		t.Fatalf("GetBytesValue(1) = %v", v)
+	}
+	if cap(v) != len(v) {
+		t.Fatalf("heap values must not expose the rest of the heap")
+	}
+	if &GetBytesValue(b, 1)[0] != &v[0] {
+		t.Fatalf("heap values must be slices of the filter")
+	}
+}
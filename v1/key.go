@@ -0,0 +1,26 @@
+package v1
+
+import (
+	"reflect"
+	"sync"
+)
+
+// KeyEncoder is implemented by key types which encode themselves deterministically.
+// AppendKey appends the encoding of the key to dst, distinct keys must encode differently.
+type KeyEncoder interface {
+	AppendKey(dst []byte) []byte
+}
+
+// keyEncoders holds the encoders registered by RegisterKeyEncoder by key type
+var keyEncoders sync.Map
+
+// RegisterKeyEncoder registers the encoding of key type K, for types which can't implement KeyEncoder.
+// No encoders are registered by default, keys without one are encoded by encoding/json. Encoders of
+// string, bool and numeric types are never used. Register encoders before creating filters, changing
+// the encoding invalidates filters created earlier.
+func RegisterKeyEncoder[K comparable](enc func(dst []byte, key K) []byte) {
+	var key K
+	keyEncoders.Store(reflect.TypeOf(&key).Elem(), func(dst []byte, key any) []byte {
+		return enc(dst, key.(K))
+	})
+}
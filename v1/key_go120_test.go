@@ -0,0 +1,15 @@
+//go:build go1.20
+
+package v1
+
+import "testing"
+
+func TestNilInterfaceKey(t *testing.T) {
+	m := map[any]bool{nil: true, 1: false, "a": true}
+	f := Make(m, 1)
+	for k, v := range m {
+		if got := GetBool(f, k); got != v {
+			t.Fatalf("GetBool(%v) = %v want %v", k, got, v)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/netip"
+	"testing"
+	"time"
+)
+
+type versionKey struct {
+	major, minor uint16
+}
+
+func (k versionKey) AppendKey(dst []byte) []byte {
+	return append(dst, byte(k.major>>8), byte(k.major), byte(k.minor>>8), byte(k.minor))
+}
+
+type opaqueKey struct {
+	id int
+}
+
+// registeredKey is opaqueKey with a registered encoder
+type registeredKey struct {
+	id int
+}
+
+func init() {
+	RegisterKeyEncoder(func(dst []byte, key registeredKey) []byte {
+		return append(dst, byte(key.id))
+	})
+}
+
+// pointerKey is a pointer key encoding its pointee
+type pointerKey struct {
+	id byte
+}
+
+func (k *pointerKey) AppendKey(dst []byte) []byte {
+	return append(dst, k.id)
+}
+
+func TestKeyEncoder(t *testing.T) {
+	m := map[versionKey]uint8{{1, 2}: 12, {2, 1}: 21, {0, 0}: 0, {300, 4}: 44}
+	f := Make(m, 8)
+	for k, v := range m {
+		if got := uint8(GetNum(f, 8, k)); got != v {
+			t.Fatalf("GetNum(%v) = %d want %d", k, got, v)
+		}
+	}
+}
+
+func TestStandardKeys(t *testing.T) {
+	addrs := map[netip.Addr]bool{
+		netip.MustParseAddr("10.0.0.1"):        true,
+		netip.MustParseAddr("::ffff:10.0.0.1"): false,
+		netip.MustParseAddr("2001:db8::1"):     true,
+	}
+	fa := Make(addrs, 1)
+	for k, v := range addrs {
+		if got := GetBool(fa, k); got != v {
+			t.Fatalf("GetBool(%v) = %v want %v", k, got, v)
+		}
+	}
+
+	at := time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC)
+	times := map[time.Time]uint16{at: 1, at.Add(time.Nanosecond): 2, at.In(time.FixedZone("x", 3600)): 3}
+	ft := Make(times, 16)
+	for k, v := range times {
+		if got := uint16(GetNum(ft, 16, k)); got != v {
+			t.Fatalf("GetNum(%v) = %d want %d", k, got, v)
+		}
+	}
+
+	uuids := map[[16]byte]bool{{1}: true, {2}: false, {15: 1}: true}
+	fu := Make(uuids, 1)
+	for k, v := range uuids {
+		if got := GetBool(fu, k); got != v {
+			t.Fatalf("GetBool(%x) = %v want %v", k, got, v)
+		}
+	}
+
+	// keys without a registered encoder keep the json encoding of filters created before
+	for k, got := range map[any][]byte{
+		[16]byte{1}:                     comparableToBytes([16]byte{1}),
+		netip.MustParseAddr("10.0.0.1"): comparableToBytes(netip.MustParseAddr("10.0.0.1")),
+		at:                              comparableToBytes(at),
+	} {
+		if want, _ := json.Marshal(k); string(got) != string(want) {
+			t.Fatalf("key %v encodes as %s want %s", k, got, want)
+		}
+	}
+
+	if string(comparableToBytes(float32(1))) == string(comparableToBytes(float64(1))) {
+		t.Fatalf("float32 and float64 keys must encode differently")
+	}
+}
+
+func TestKeyCollisionDetected(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatalf("expected panic when distinct keys encode identically")
+		}
+	}()
+	// unexported fields are invisible to the json fallback
+	Make(map[opaqueKey]bool{{1}: true, {2}: false}, 1)
+}
+
+func TestFloat32KeyEncoding(t *testing.T) {
+	// float32 keys keep the 8 byte encoding of filters created before
+	want := []byte{0, 0, 0, 0, 0x3f, 0x80, 0, 0}
+	if got := comparableToBytes(float32(1)); string(got) != string(want) {
+		t.Fatalf("comparableToBytes(float32(1)) = %x want %x", got, want)
+	}
+}
+
+func TestRegisterKeyEncoder(t *testing.T) {
+	m := map[registeredKey]uint8{{1}: 10, {2}: 20, {3}: 30}
+	f := Make(m, 8)
+	for k, v := range m {
+		if got := uint8(GetNum(f, 8, k)); got != v {
+			t.Fatalf("GetNum(%v) = %d want %d", k, got, v)
+		}
+	}
+}
+
+func TestPointerKeys(t *testing.T) {
+	a, b := &pointerKey{1}, &pointerKey{2}
+	f := Make(map[*pointerKey]bool{a: true, b: false}, 1)
+	if !GetBool(f, a) || GetBool(f, b) {
+		t.Fatalf("pointer keys implementing KeyEncoder returned wrong values")
+	}
+
+	x, y := 1, 1
+	// lookups of pointer keys don't panic
+	GetBool(f, &pointerKey{3})
+	GetNum(Make(map[int]uint8{1: 1}, 8), 8, &x)
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatalf("expected panic for pointer keys without an encoder")
+		}
+	}()
+	Make(map[*int]bool{&x: true, &y: false}, 1)
+}
@@ -5,14 +5,18 @@ import sha256 "github.com/minio/sha256-simd"
 
 // get checks if an array exists in the Filters.
 func get(f []byte, data []byte, anslen uint64, funcs byte) (ret []byte) {
+	datb := sha256.Sum256(data)
+	return getHashed(f, &datb, anslen, funcs)
+}
+
+// getHashed is get for a key already hashed to datb
+func getHashed(f []byte, datb *[32]byte, anslen uint64, funcs byte) (ret []byte) {
 	if len(f) <= 0 {
 		return nil
 	}
 	if anslen == 0 {
 		return nil
 	}
-	var datb [32]byte
-	datb = sha256.Sum256(data)
 
 	baseSize := uint64(len(f))
 
@@ -142,14 +146,18 @@ outer:
 // getInto is an optimized version of get that writes into pre-allocated buffers
 // Returns true if any bits were resolved (key found), false otherwise
 func getInto(f []byte, data []byte, ret []byte, done []byte, anslen uint64, funcs byte) bool {
+	datb := sha256.Sum256(data)
+	return getIntoHashed(f, &datb, ret, done, anslen, funcs)
+}
+
+// getIntoHashed is getInto for a key already hashed to datb
+func getIntoHashed(f []byte, datb *[32]byte, ret []byte, done []byte, anslen uint64, funcs byte) bool {
 	if len(f) <= 0 {
 		return false
 	}
 	if anslen == 0 {
 		return false
 	}
-	var datb [32]byte
-	datb = sha256.Sum256(data)
 
 	baseSize := uint64(len(f))
 
@@ -0,0 +1,165 @@
+package v1
+
+import "reflect"
+
+// Options configure the filter built by NewMap
+type Options struct {
+	// BitLimit is the bit size numeric values are stored with, 0 selects the width of V
+	BitLimit byte
+	// BloomFuncs is the number of bloom functions, see New
+	BloomFuncs byte
+	// FingerprintBits is the key fingerprint size of numeric values, see NewVerified
+	FingerprintBits byte
+}
+
+// Map is a typed view of a filter which remembers the kind and the width of its values
+type Map[K comparable, V Value] struct {
+	f []byte
+}
+
+// valueKind identifies the value type V in the Map metadata
+func valueKind[V Value]() byte {
+	var v V
+	return byte(reflect.TypeOf(&v).Elem().Kind())
+}
+
+// isVarLen reports whether values of type V are stored by NewVarLen
+func isVarLen[V Value]() bool {
+	var v V
+	switch any(v).(type) {
+	case string, []byte:
+		return true
+	}
+	return false
+}
+
+// varLenBytes returns the bytes of a string or []byte value
+func varLenBytes[V Value](v V) []byte {
+	switch val := any(v).(type) {
+	case string:
+		return []byte(val)
+	case []byte:
+		return val
+	}
+	return nil
+}
+
+// NewMap generates the Map based on map m. String and []byte values are stored by NewVarLen,
+// numeric values by New or, with opts.FingerprintBits, by NewVerified.
+func NewMap[K comparable, V Value](m map[K]V, opts Options) Map[K, V] {
+	var inner []byte
+	width := opts.BitLimit
+	if isVarLen[V]() {
+		if opts.FingerprintBits != 0 {
+			panic("fingerprints are not supported for string and []byte values")
+		}
+		vals := make(map[K][]byte, len(m))
+		for k, v := range m {
+			vals[k] = varLenBytes(v)
+		}
+		inner = NewVarLen(vals, opts.BloomFuncs)
+	} else {
+		if width == 0 || nativeBitLimit[V]() == 1 {
+			width = nativeBitLimit[V]()
+		}
+		if opts.FingerprintBits != 0 {
+			inner = NewVerified(m, width, opts.BloomFuncs, opts.FingerprintBits)
+		} else {
+			inner = New(m, width, opts.BloomFuncs)
+		}
+	}
+	return Map[K, V]{wrap(kindMap, []byte{valueKind[V](), width, opts.FingerprintBits}, inner)}
+}
+
+// LoadMap returns the Map stored in b, as returned by Bytes. It panics if the values
+// are not of type V.
+func LoadMap[K comparable, V Value](b []byte) Map[K, V] {
+	meta, _ := unwrap(b, kindMap)
+	if len(meta) < 3 {
+		panic("filter metadata truncated")
+	}
+	if meta[0] != valueKind[V]() {
+		panic("map values are not of the requested type")
+	}
+	return Map[K, V]{b}
+}
+
+// Bytes returns the serialized Map
+func (m Map[K, V]) Bytes() []byte {
+	return m.f
+}
+
+// Lookup retrieves the value of key, ok is false if the key surely wasn't inserted.
+// Absent keys are only detected with BloomFuncs or FingerprintBits.
+func (m Map[K, V]) Lookup(key K) (value V, ok bool) {
+	meta, inner := unwrap(m.f, kindMap)
+	width, fingerprintBits := meta[1], meta[2]
+	var data []byte
+	switch {
+	case isVarLen[V]():
+		data = getVarLen(inner, comparableToBytes(key))
+	case fingerprintBits != 0:
+		data, _ = GetVerified(inner, uint64(width), key)
+	case len(inner) > 2:
+		data = Get(inner, uint64(width), key)
+	}
+	if data == nil {
+		return value, false
+	}
+	return decodeValue[V](data, width), true
+}
+
+// Get retrieves the value of key, absent keys return a garbage or zero value
+func (m Map[K, V]) Get(key K) V {
+	value, _ := m.Lookup(key)
+	return value
+}
+
+// GetOr retrieves the value of key, or def if the key surely wasn't inserted
+func (m Map[K, V]) GetOr(key K, def V) V {
+	if value, ok := m.Lookup(key); ok {
+		return value
+	}
+	return def
+}
+
+// decodeValue converts the big-endian value bits of the given width back into V
+func decodeValue[V Value](data []byte, width byte) (value V) {
+	var num uint64
+	for _, b := range data {
+		num = num<<8 | uint64(b)
+	}
+	switch p := any(&value).(type) {
+	case *bool:
+		*p = num&1 == 1
+	case *uint8:
+		*p = uint8(num)
+	case *uint16:
+		*p = uint16(num)
+	case *uint32:
+		*p = uint32(num)
+	case *uint64:
+		*p = num
+	case *uint:
+		*p = uint(num)
+	case *int:
+		*p = int(signExtend(num, uint64(width)))
+	case *int8:
+		*p = int8(signExtend(num, uint64(width)))
+	case *int16:
+		*p = int16(signExtend(num, uint64(width)))
+	case *int32:
+		*p = int32(signExtend(num, uint64(width)))
+	case *int64:
+		*p = signExtend(num, uint64(width))
+	case *float32:
+		*p = float32(floatOf(num, uint64(width)))
+	case *float64:
+		*p = floatOf(num, uint64(width))
+	case *string:
+		*p = string(data)
+	case *[]byte:
+		*p = data
+	}
+	return
+}
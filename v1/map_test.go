@@ -0,0 +1,71 @@
+package v1
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestMap(t *testing.T) {
+	temps := make(map[string]int16)
+	for i := 0; i < 1000; i++ {
+		temps[fmt.Sprint("city", i)] = int16(i%200 - 100)
+	}
+	m := NewMap(temps, Options{BitLimit: 9})
+	for k, v := range temps {
+		if got := m.Get(k); got != v {
+			t.Fatalf("Get(%s) = %d want %d", k, got, v)
+		}
+	}
+	loaded := LoadMap[string, int16](append([]byte(nil), m.Bytes()...))
+	if got := loaded.Get("city7"); got != temps["city7"] {
+		t.Fatalf("loaded Get(city7) = %d want %d", got, temps["city7"])
+	}
+
+	verified := NewMap(temps, Options{FingerprintBits: 12})
+	var found int
+	for i := 0; i < 1000; i++ {
+		if v, ok := verified.Lookup(fmt.Sprint("city", i)); ok && v == temps[fmt.Sprint("city", i)] {
+			found++
+		}
+		if _, ok := verified.Lookup(fmt.Sprint("absent", i)); ok {
+			found--
+		}
+	}
+	if found < 995 {
+		t.Fatalf("Lookup found %d present keys net of absent ones", found)
+	}
+	if got := verified.GetOr("absent", 1234); got != 1234 {
+		t.Fatalf("GetOr(absent) = %d want 1234", got)
+	}
+
+	names := NewMap(map[int]string{1: "one", 2: "", 3: "three"}, Options{BloomFuncs: 8})
+	for k, v := range map[int]string{1: "one", 2: "", 3: "three"} {
+		if got, ok := names.Lookup(k); !ok || got != v {
+			t.Fatalf("Lookup(%d) = %q, %v want %q, true", k, got, ok, v)
+		}
+	}
+	if got := names.GetOr(4, "none"); got != "none" {
+		t.Fatalf("GetOr(4) = %q want none", got)
+	}
+
+	flags := NewMap(map[int]bool{1: true, 2: false}, Options{BitLimit: 8})
+	if !flags.Get(1) || flags.Get(2) {
+		t.Fatalf("bool Map returned wrong values")
+	}
+	ratios := NewMap(map[int]float32{1: 0.5, 2: -2}, Options{})
+	if ratios.Get(1) != 0.5 || ratios.Get(2) != -2 {
+		t.Fatalf("float Map returned %v, %v", ratios.Get(1), ratios.Get(2))
+	}
+	if _, ok := NewMap(map[int]uint8{}, Options{}).Lookup(1); ok {
+		t.Fatalf("empty Map reported a key")
+	}
+}
+
+func TestLoadMapPanicsOnWrongType(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatalf("expected panic loading int16 values as uint16")
+		}
+	}()
+	LoadMap[int, uint16](NewMap(map[int]int16{1: 1}, Options{}).Bytes())
+}
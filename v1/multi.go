@@ -0,0 +1,166 @@
+package v1
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// NewMulti generates the map based on map m of value lists. Every list is packed as
+// a count followed by the values and stored by NewHeap, so equal lists are stored once.
+// Numbers are packed as varints, floats as their bits and strings length prefixed.
+func NewMulti[K comparable, V Value](m map[K][]V, bloomFuncs byte) []byte {
+	lists := make(map[K][]byte, len(m))
+	for k, list := range m {
+		packed := appendUvarint(nil, uint64(len(list)))
+		for _, v := range list {
+			packed = appendElem(packed, v)
+		}
+		lists[k] = packed
+	}
+	return NewHeap(lists, bloomFuncs)
+}
+
+// GetAll retrieves the list of values based on comparable key from a filter created by NewMulti,
+// nil if the key surely wasn't inserted
+func GetAll[K comparable, V Value](f []byte, key K) []V {
+	packed := getHeap(f, comparableToBytes(key))
+	count, l := binary.Uvarint(packed)
+	if l <= 0 || count > uint64(len(packed)) {
+		return nil
+	}
+	packed = packed[l:]
+	list := make([]V, count)
+	for i := range list {
+		v, n := elemOf[V](packed)
+		if n <= 0 {
+			// garbage list of an absent key
+			return nil
+		}
+		list[i] = v
+		packed = packed[n:]
+	}
+	return list
+}
+
+// appendElem appends the packed value v to dst
+func appendElem[V Value](dst []byte, v V) []byte {
+	switch val := any(v).(type) {
+	case string:
+		dst = appendUvarint(dst, uint64(len(val)))
+		return append(dst, val...)
+	case []byte:
+		dst = appendUvarint(dst, uint64(len(val)))
+		return append(dst, val...)
+	case bool:
+		if val {
+			return append(dst, 1)
+		}
+		return append(dst, 0)
+	case uint8:
+		return appendUvarint(dst, uint64(val))
+	case uint16:
+		return appendUvarint(dst, uint64(val))
+	case uint32:
+		return appendUvarint(dst, uint64(val))
+	case uint64:
+		return appendUvarint(dst, val)
+	case uint:
+		return appendUvarint(dst, uint64(val))
+	case int:
+		return appendVarint(dst, int64(val))
+	case int8:
+		return appendVarint(dst, int64(val))
+	case int16:
+		return appendVarint(dst, int64(val))
+	case int32:
+		return appendVarint(dst, int64(val))
+	case int64:
+		return appendVarint(dst, val)
+	case float32:
+		var b [4]byte
+		binary.BigEndian.PutUint32(b[:], math.Float32bits(val))
+		return append(dst, b[:]...)
+	case float64:
+		var b [8]byte
+		binary.BigEndian.PutUint64(b[:], math.Float64bits(val))
+		return append(dst, b[:]...)
+	}
+	return dst
+}
+
+// appendUvarint appends the uvarint encoding of x to dst
+func appendUvarint(dst []byte, x uint64) []byte {
+	var b [binary.MaxVarintLen64]byte
+	return append(dst, b[:binary.PutUvarint(b[:], x)]...)
+}
+
+// appendVarint appends the varint encoding of x to dst
+func appendVarint(dst []byte, x int64) []byte {
+	var b [binary.MaxVarintLen64]byte
+	return append(dst, b[:binary.PutVarint(b[:], x)]...)
+}
+
+// elemOf unpacks a value appended by appendElem, n is the number of bytes read or
+// not positive if data is malformed
+func elemOf[V Value](data []byte) (value V, n int) {
+	switch p := any(&value).(type) {
+	case *string, *[]byte:
+		size, l := binary.Uvarint(data)
+		if l <= 0 || size > uint64(len(data)-l) {
+			return value, 0
+		}
+		if s, ok := p.(*string); ok {
+			*s = string(data[l : l+int(size)])
+		} else {
+			*p.(*[]byte) = data[l : l+int(size) : l+int(size)]
+		}
+		return value, l + int(size)
+	case *bool:
+		if len(data) == 0 {
+			return value, 0
+		}
+		*p = data[0] == 1
+		return value, 1
+	case *float32:
+		if len(data) < 4 {
+			return value, 0
+		}
+		*p = math.Float32frombits(binary.BigEndian.Uint32(data))
+		return value, 4
+	case *float64:
+		if len(data) < 8 {
+			return value, 0
+		}
+		*p = math.Float64frombits(binary.BigEndian.Uint64(data))
+		return value, 8
+	case *int, *int8, *int16, *int32, *int64:
+		num, l := binary.Varint(data)
+		switch p := p.(type) {
+		case *int:
+			*p = int(num)
+		case *int8:
+			*p = int8(num)
+		case *int16:
+			*p = int16(num)
+		case *int32:
+			*p = int32(num)
+		case *int64:
+			*p = num
+		}
+		return value, l
+	}
+	num, l := binary.Uvarint(data)
+	switch p := any(&value).(type) {
+	case *uint8:
+		*p = uint8(num)
+	case *uint16:
+		*p = uint16(num)
+	case *uint32:
+		*p = uint32(num)
+	case *uint64:
+		*p = num
+	case *uint:
+		*p = uint(num)
+	}
+	return value, l
+}
@@ -0,0 +1,75 @@
+package v1
+
+import (
+	"fmt"
+	"testing"
+	"unsafe"
+)
+
+// tagIndex maps entities to 0 to 20 tags out of 100
+func tagIndex(n int) map[string][]string {
+	m := make(map[string][]string, n)
+	for i := 0; i < n; i++ {
+		var tags []string
+		for j := 0; j < i%21; j++ {
+			tags = append(tags, fmt.Sprint("tag", (i*7+j*13)%100))
+		}
+		m[fmt.Sprint("entity", i)] = tags
+	}
+	return m
+}
+
+// sizeOfMultiMap approximates the memory held by a map[string][]string,
+// counting the entries, the slices and the strings but not the map buckets
+func sizeOfMultiMap(m map[string][]string) (size int) {
+	for k, list := range m {
+		size += int(unsafe.Sizeof(k)) + len(k) + int(unsafe.Sizeof(list))
+		for _, v := range list {
+			size += int(unsafe.Sizeof(v)) + len(v)
+		}
+	}
+	return
+}
+
+func TestMultiTags(t *testing.T) {
+	m := tagIndex(2000)
+	f := NewMulti(m, 0)
+	for k, v := range m {
+		if got := GetAll[string, string](f, k); fmt.Sprint(got) != fmt.Sprint(v) || len(got) != len(v) {
+			t.Fatalf("GetAll(%s) = %v want %v", k, got, v)
+		}
+	}
+	// the sizes quoted by the README
+	mapSize := sizeOfMultiMap(m)
+	if len(f) > 140000 || mapSize < 516000 {
+		t.Fatalf("NewMulti takes %d bytes, map[string][]string %d bytes, want at most 140 kB and at least 516 kB", len(f), mapSize)
+	}
+}
+
+func TestMultiNumbers(t *testing.T) {
+	m := map[int][]int16{1: {-1, 2, -300}, 2: {}, 3: {32767}}
+	f := NewMulti(m, 0)
+	for k, v := range m {
+		if got := GetAll[int, int16](f, k); fmt.Sprint(got) != fmt.Sprint(v) {
+			t.Fatalf("GetAll(%d) = %v want %v", k, got, v)
+		}
+	}
+	fl := NewMulti(map[string][]float32{"a": {0.5, -1}, "b": {3}}, 0)
+	if got := GetAll[string, float32](fl, "a"); len(got) != 2 || got[0] != 0.5 || got[1] != -1 {
+		t.Fatalf("GetAll(a) = %v", got)
+	}
+	bl := NewMulti(map[string][]bool{"a": {true, false, true}}, 0)
+	if got := GetAll[string, bool](bl, "a"); fmt.Sprint(got) != "[true false true]" {
+		t.Fatalf("GetAll(a) = %v", got)
+	}
+}
+
+func BenchmarkSizeMulti(b *testing.B) {
+	m := tagIndex(10000)
+	var f []byte
+	for i := 0; i < b.N; i++ {
+		f = NewMulti(m, 0)
+	}
+	b.ReportMetric(float64(len(f)), "filter_bytes")
+	b.ReportMetric(float64(sizeOfMultiMap(m)), "map_bytes")
+}
@@ -0,0 +1,153 @@
+package v1
+
+import (
+	"encoding/binary"
+	"net/netip"
+)
+
+// prefixLengths is the number of distinct prefix lengths, 0 to 32 for IPv4 and 0 to 128 for IPv6
+const prefixLengths = 33 + 129
+
+// prefixHeader is the size of the value kind, the width, the fingerprint size and the length bitmap
+const prefixHeader = 3 + (prefixLengths+7)/8
+
+// prefixLength returns the index of the prefix length of p in the length bitmap
+func prefixLength(p netip.Prefix) int {
+	if p.Addr().Is4() {
+		return p.Bits()
+	}
+	return 33 + p.Bits()
+}
+
+// PrefixTable maps IP prefixes to values and finds the value of the longest prefix matching an address.
+// It holds a NewVerified filter per prefix length present, keyed by the masked prefixes, and a bitmap
+// of the lengths present. String and []byte values are stored once in a dictionary, see NewDict.
+type PrefixTable[V Value] struct {
+	f []byte
+}
+
+// NewPrefixTable generates the PrefixTable based on map m of possibly overlapping prefixes.
+// Lookup reports a wrong value for an address with rate 2^-fingerprintBits per prefix
+// length longer than its true match, so pick fingerprintBits with the count of lengths in mind.
+func NewPrefixTable[V Value](m map[netip.Prefix]V, bitLimit, fingerprintBits byte) PrefixTable[V] {
+	if fingerprintBits == 0 {
+		panic("prefix table needs a fingerprint to reject the lengths not matching")
+	}
+	var table []byte
+	var codes map[string]uint64
+	width := bitLimit
+	if isVarLen[V]() {
+		distinct := make(map[string]struct{})
+		for _, v := range m {
+			distinct[string(varLenBytes(v))] = struct{}{}
+		}
+		table, codes, width = dictionary(distinct)
+	} else if width == 0 || nativeBitLimit[V]() == 1 {
+		width = nativeBitLimit[V]()
+	}
+
+	groups := make([]map[netip.Prefix]V, prefixLengths)
+	for p, v := range m {
+		if !p.IsValid() {
+			panic("invalid prefix")
+		}
+		i := prefixLength(p)
+		if groups[i] == nil {
+			groups[i] = make(map[netip.Prefix]V)
+		}
+		if _, ok := groups[i][p.Masked()]; ok {
+			panic("distinct prefixes mask to the same network")
+		}
+		groups[i][p.Masked()] = v
+	}
+
+	meta := make([]byte, prefixHeader)
+	meta[0], meta[1], meta[2] = valueKind[V](), width, fingerprintBits
+	var core []byte
+	for i, group := range groups {
+		if group == nil {
+			continue
+		}
+		meta[3+i/8] |= 1 << (i % 8)
+		var section []byte
+		if codes != nil {
+			coded := make(map[netip.Prefix]uint64, len(group))
+			for p, v := range group {
+				coded[p] = codes[string(varLenBytes(v))]
+			}
+			section = NewVerified(coded, width, 0, fingerprintBits)
+		} else {
+			section = NewVerified(group, width, 0, fingerprintBits)
+		}
+		meta = appendUvarint(meta, uint64(len(section)))
+		core = append(core, section...)
+	}
+	return PrefixTable[V]{wrap(kindPrefix, append(meta, table...), core)}
+}
+
+// LoadPrefixTable returns the PrefixTable stored in b, as returned by Bytes. It panics if
+// the values are not of type V.
+func LoadPrefixTable[V Value](b []byte) PrefixTable[V] {
+	meta, _ := unwrap(b, kindPrefix)
+	if len(meta) < prefixHeader {
+		panic("filter metadata truncated")
+	}
+	if meta[0] != valueKind[V]() {
+		panic("prefix table values are not of the requested type")
+	}
+	return PrefixTable[V]{b}
+}
+
+// Bytes returns the serialized PrefixTable
+func (t PrefixTable[V]) Bytes() []byte {
+	return t.f
+}
+
+// Lookup retrieves the value of the longest prefix containing addr, ok is false if there is none
+func (t PrefixTable[V]) Lookup(addr netip.Addr) (value V, ok bool) {
+	meta, core := unwrap(t.f, kindPrefix)
+	width := meta[1]
+	bitmap := meta[3:prefixHeader]
+
+	// find the sections of the lengths present, the table follows the section sizes
+	var sections [prefixLengths][]byte
+	rest := meta[prefixHeader:]
+	for i := 0; i < prefixLengths; i++ {
+		if bitmap[i/8]&(1<<(i%8)) == 0 {
+			continue
+		}
+		size, l := binary.Uvarint(rest)
+		if l <= 0 || size > uint64(len(core)) {
+			panic("filter metadata truncated")
+		}
+		sections[i], core, rest = core[:size], core[size:], rest[l:]
+	}
+	table := rest
+
+	lo, hi := 0, 32
+	if !addr.Is4() {
+		lo, hi = 33, 33+128
+	}
+	for i := hi; i >= lo; i-- {
+		if sections[i] == nil {
+			continue
+		}
+		p, err := addr.Prefix(i - lo)
+		if err != nil {
+			continue
+		}
+		data, found := GetVerified(sections[i], uint64(width), p)
+		if !found {
+			continue
+		}
+		if len(table) == 0 {
+			return decodeValue[V](data, width), true
+		}
+		elem := dictValue(table, decodeValue[uint64](data, width))
+		if elem == nil {
+			continue
+		}
+		return decodeValue[V](elem, 0), true
+	}
+	return value, false
+}
@@ -0,0 +1,71 @@
+package v1
+
+import (
+	"net/netip"
+	"testing"
+)
+
+func TestPrefixTable(t *testing.T) {
+	m := map[netip.Prefix]uint32{
+		netip.MustParsePrefix("0.0.0.0/0"):       1,
+		netip.MustParsePrefix("10.0.0.0/8"):      64512,
+		netip.MustParsePrefix("10.1.0.0/16"):     64513,
+		netip.MustParsePrefix("10.1.2.0/24"):     64514,
+		netip.MustParsePrefix("10.1.2.3/32"):     64515,
+		netip.MustParsePrefix("192.168.7.9/16"):  64516, // masked to 192.168.0.0/16
+		netip.MustParsePrefix("2001:db8::/32"):   65000,
+		netip.MustParsePrefix("2001:db8:1::/48"): 65001,
+	}
+	for i := 0; i < 1000; i++ {
+		m[netip.PrefixFrom(netip.AddrFrom4([4]byte{100, byte(i >> 8), byte(i), 0}), 24)] = uint32(70000 + i)
+	}
+	table := NewPrefixTable(m, 0, 16)
+	for addr, want := range map[string]uint32{
+		"10.9.9.9":       64512,
+		"10.1.9.9":       64513,
+		"10.1.2.9":       64514,
+		"10.1.2.3":       64515,
+		"192.168.200.1":  64516,
+		"8.8.8.8":        1,
+		"100.0.5.77":     70005,
+		"100.3.231.1":    70999,
+		"2001:db8:2::1":  65000,
+		"2001:db8:1:5::": 65001,
+	} {
+		got, ok := table.Lookup(netip.MustParseAddr(addr))
+		if !ok || got != want {
+			t.Fatalf("Lookup(%s) = %d, %v want %d, true", addr, got, ok, want)
+		}
+	}
+	if _, ok := table.Lookup(netip.MustParseAddr("2a00::1")); ok {
+		t.Fatalf("Lookup(2a00::1) matched without an IPv6 default route")
+	}
+
+	loaded := LoadPrefixTable[uint32](table.Bytes())
+	if got, _ := loaded.Lookup(netip.MustParseAddr("10.1.2.3")); got != 64515 {
+		t.Fatalf("loaded Lookup(10.1.2.3) = %d want 64515", got)
+	}
+}
+
+func TestPrefixTableCountries(t *testing.T) {
+	countries := []string{"CZ", "SK", "DE", "US"}
+	m := make(map[netip.Prefix]string)
+	for i := 0; i < 256; i++ {
+		m[netip.PrefixFrom(netip.AddrFrom4([4]byte{byte(i), 0, 0, 0}), 8)] = countries[i%4]
+		m[netip.PrefixFrom(netip.AddrFrom4([4]byte{byte(i), 128, 0, 0}), 9)] = countries[(i+1)%4]
+	}
+	table := NewPrefixTable(m, 0, 12)
+	for i := 0; i < 256; i++ {
+		low := netip.AddrFrom4([4]byte{byte(i), 1, 2, 3})
+		high := netip.AddrFrom4([4]byte{byte(i), 200, 2, 3})
+		if got, ok := table.Lookup(low); !ok || got != countries[i%4] {
+			t.Fatalf("Lookup(%s) = %q, %v want %q", low, got, ok, countries[i%4])
+		}
+		if got, ok := table.Lookup(high); !ok || got != countries[(i+1)%4] {
+			t.Fatalf("Lookup(%s) = %q, %v want %q", high, got, ok, countries[(i+1)%4])
+		}
+	}
+	if got, ok := table.Lookup(netip.MustParseAddr("::1")); ok {
+		t.Fatalf("Lookup(::1) = %q without IPv6 prefixes", got)
+	}
+}
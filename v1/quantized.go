@@ -0,0 +1,143 @@
+package v1
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// Scale selects how the quantization codes are spaced between min and max
+type Scale byte
+
+const (
+	// Linear spaces the codes evenly between min and max
+	Linear Scale = iota
+	// Logarithmic spaces the codes evenly between log(min) and log(max), min must be positive
+	Logarithmic
+)
+
+// quantizer maps floats between min and max onto codes of the given bit size and back
+type quantizer struct {
+	scale    Scale
+	bits     byte
+	min, max float64
+}
+
+// quantizedMagic opens the quantizer metadata, so that GetFloat tells quantized filters from raw ones
+const quantizedMagic = "\xffqnt"
+
+// quantizerMetaSize is the size of the quantizer encoded as filter metadata
+const quantizerMetaSize = len(quantizedMagic) + 2 + 8 + 8
+
+func newQuantizer(scale Scale, bits byte, min, max float64) quantizer {
+	if bits == 0 || bits > 32 {
+		panic("quantized values must be 1 to 32 bits wide")
+	}
+	if !(min < max) {
+		panic("quantization range must have min below max")
+	}
+	if scale == Logarithmic && !(min > 0) {
+		panic("logarithmic quantization range must be positive")
+	}
+	if scale > Logarithmic {
+		panic("unknown quantization scale")
+	}
+	return quantizer{scale: scale, bits: bits, min: min, max: max}
+}
+
+func (q quantizer) meta() []byte {
+	meta := make([]byte, quantizerMetaSize)
+	n := copy(meta, quantizedMagic)
+	meta[n] = byte(q.scale)
+	meta[n+1] = q.bits
+	binary.BigEndian.PutUint64(meta[n+2:], math.Float64bits(q.min))
+	binary.BigEndian.PutUint64(meta[n+10:], math.Float64bits(q.max))
+	return meta
+}
+
+// quantizedOf returns the quantizer and the core filter of a filter created by newQuantized,
+// ok is false for any other filter
+func quantizedOf(f []byte) (q quantizer, core []byte, ok bool) {
+	// the metadata length fits the single byte uvarint following the kind
+	const header = 2 + len(quantizedMagic)
+	if len(f) < 2+quantizerMetaSize || f[0] != kindQuantized || f[1] != byte(quantizerMetaSize) ||
+		string(f[2:header]) != quantizedMagic {
+		return q, nil, false
+	}
+	q = quantizer{
+		scale: Scale(f[header]),
+		bits:  f[header+1],
+		min:   math.Float64frombits(binary.BigEndian.Uint64(f[header+2:])),
+		max:   math.Float64frombits(binary.BigEndian.Uint64(f[header+10:])),
+	}
+	if q.scale > Logarithmic || q.bits == 0 || q.bits > 32 || !(q.min < q.max) {
+		return q, nil, false
+	}
+	return q, f[2+quantizerMetaSize:], true
+}
+
+// span returns the range ends in the space the codes are evenly spaced in
+func (q quantizer) span() (lo, hi float64) {
+	if q.scale == Logarithmic {
+		return math.Log(q.min), math.Log(q.max)
+	}
+	return q.min, q.max
+}
+
+// code rounds value to the nearest code, values outside of the range are clamped
+func (q quantizer) code(value float64) uint32 {
+	top := float64(uint64(1)<<q.bits - 1)
+	lo, hi := q.span()
+	if q.scale == Logarithmic {
+		if !(value > 0) {
+			return 0
+		}
+		value = math.Log(value)
+	}
+	x := math.Round((value - lo) / (hi - lo) * top)
+	if !(x > 0) {
+		return 0
+	}
+	if x > top {
+		return uint32(top)
+	}
+	return uint32(x)
+}
+
+func (q quantizer) value(code uint64) float64 {
+	top := float64(uint64(1)<<q.bits - 1)
+	lo, hi := q.span()
+	x := lo + float64(code)/top*(hi-lo)
+	if q.scale == Logarithmic {
+		return math.Exp(x)
+	}
+	return x
+}
+
+// NewQuantized generates the map based on map m, storing every value as a bits wide code
+// evenly spaced between min and max, GetFloat dequantizes it. Values outside of the range are clamped.
+// The error of a value within the range is at most (max-min)/(2^bits-1)/2.
+func NewQuantized[K comparable](m map[K]float64, bits byte, min, max float64) []byte {
+	return newQuantized(m, newQuantizer(Linear, bits, min, max))
+}
+
+// NewQuantizedLog is NewQuantized with the codes evenly spaced between log(min) and log(max),
+// so that the relative error is bounded instead of the absolute one.
+func NewQuantizedLog[K comparable](m map[K]float64, bits byte, min, max float64) []byte {
+	return newQuantized(m, newQuantizer(Logarithmic, bits, min, max))
+}
+
+func newQuantized[K comparable](m map[K]float64, q quantizer) []byte {
+	codes := make(map[K]uint32, len(m))
+	for k, v := range m {
+		codes[k] = q.code(v)
+	}
+	return wrap(kindQuantized, q.meta(), Make(codes, q.bits))
+}
+
+// getQuantized retrieves the dequantized value of key from the core filter of quantizer q
+func getQuantized[K comparable](q quantizer, core []byte, key K) float64 {
+	if len(core) <= 2 {
+		return q.value(0)
+	}
+	return q.value(GetNum(core, uint64(q.bits), key))
+}
@@ -0,0 +1,67 @@
+package v1
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestQuantized(t *testing.T) {
+	m := make(map[string]float64)
+	for i := 0; i < 1000; i++ {
+		m[fmt.Sprint("key", i)] = math.Sin(float64(i))
+	}
+	for _, bits := range []byte{1, 6, 10, 32} {
+		f := NewQuantized(m, bits, -1, 1)
+		step := 2 / float64(uint64(1)<<bits-1)
+		for k, v := range m {
+			if got := GetFloat(f, uint64(bits), k); math.Abs(got-v) > step/2+1e-9 {
+				t.Fatalf("bits %d: %s = %v want %v within %v", bits, k, got, v, step/2)
+			}
+		}
+	}
+
+	clamped := NewQuantized(map[int]float64{1: -5, 2: 5, 3: math.NaN()}, 4, 0, 1)
+	if GetFloat(clamped, 4, 1) != 0 || GetFloat(clamped, 4, 2) != 1 || GetFloat(clamped, 4, 3) != 0 {
+		t.Fatalf("values outside of the range must be clamped")
+	}
+	if got := GetFloat(NewQuantized(map[int]float64{}, 8, 2, 3), 8, 1); got != 2 {
+		t.Fatalf("empty filter returned %v want 2", got)
+	}
+}
+
+func TestQuantizedLog(t *testing.T) {
+	m := make(map[int]float64)
+	for i := 0; i < 1000; i++ {
+		m[i] = math.Pow(10, float64(i%90)/10-3)
+	}
+	f := NewQuantizedLog(m, 8, 1e-3, 1e6)
+	// codes are spaced by a ratio of 1e9^(1/255)
+	ratio := math.Pow(1e9, 1/255.0/2)
+	for k, v := range m {
+		if got := GetFloat(f, 8, k); got/v > ratio*(1+1e-9) || v/got > ratio*(1+1e-9) {
+			t.Fatalf("%d = %v want %v within ratio %v", k, got, v, ratio)
+		}
+	}
+	raw := Make(m, 64)
+	if len(f)*4 > len(raw) {
+		t.Fatalf("8 bit codes take %d bytes, raw floats %d bytes", len(f), len(raw))
+	}
+}
+
+func TestGetFloatRaw(t *testing.T) {
+	// raw float filters are not mistaken for quantized ones
+	m := make(map[int]float64)
+	for i := 0; i < 1000; i++ {
+		m[i] = float64(i) / 7
+	}
+	f := Make(m, 64)
+	for k, v := range m {
+		if got := GetFloat(f, 64, k); got != v {
+			t.Fatalf("%d = %v want %v", k, got, v)
+		}
+	}
+	if _, _, ok := quantizedOf(f); ok {
+		t.Fatalf("raw filter detected as quantized")
+	}
+}
@@ -0,0 +1,42 @@
+package v1
+
+// NewSparse generates the map in which every key carries the default value def except
+// the keys of exceptions. Only the exceptions are stored, by NewVerified with a
+// fingerprintBits wide fingerprint, exceptions equal to def are dropped.
+//
+// GetSparse returns the stored value of an exception. Any other key returns def, except
+// with rate 2^-fingerprintBits when it passes the fingerprint and returns a garbage value.
+func NewSparse[K comparable, V Value](def V, exceptions map[K]V, bitLimit, fingerprintBits byte) []byte {
+	if isVarLen[V]() {
+		panic("sparse values must be numbers or bools")
+	}
+	if fingerprintBits == 0 {
+		panic("sparse map needs a fingerprint to reject the default keys")
+	}
+	if bitLimit == 0 || nativeBitLimit[V]() == 1 {
+		bitLimit = nativeBitLimit[V]()
+	}
+	stored := make(map[K]V)
+	for k, v := range exceptions {
+		if any(v) != any(def) {
+			stored[k] = v
+		}
+	}
+	width := bitLimit
+	defBytes := materialize(map[int]V{0: def}, &width)[0][1]
+	meta := append([]byte{valueKind[V](), bitLimit, fingerprintBits}, defBytes...)
+	return wrap(kindSparse, meta, NewVerified(stored, bitLimit, 0, fingerprintBits))
+}
+
+// GetSparse retrieves the value based on comparable key from a filter created by NewSparse
+func GetSparse[K comparable, V Value](f []byte, key K) V {
+	meta, inner := unwrap(f, kindSparse)
+	if len(meta) < 3 || meta[0] != valueKind[V]() {
+		panic("sparse values are not of the requested type")
+	}
+	width := meta[1]
+	if data, ok := GetVerified(inner, uint64(width), key); ok {
+		return decodeValue[V](data, width)
+	}
+	return decodeValue[V](meta[3:], width)
+}
@@ -0,0 +1,79 @@
+package v1
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSparse(t *testing.T) {
+	const n = 100000
+	exceptions := make(map[int]uint8)
+	for i := 0; i < n; i += 100 {
+		exceptions[i] = uint8(1 + i%7)
+	}
+	exceptions[1] = 0 // equal to the default, dropped
+	all := make(map[int]uint8, n)
+	for i := 0; i < n; i++ {
+		all[i] = exceptions[i]
+	}
+	full := Make(all, 3)
+	for _, bits := range []byte{4, 8} {
+		f := NewSparse(uint8(0), exceptions, 3, bits)
+		var wrong int
+		for i := 0; i < n; i++ {
+			got := GetSparse[int, uint8](f, i)
+			if want, ok := exceptions[i]; ok {
+				if got != want {
+					t.Fatalf("fingerprint %d: exception %d = %d want %d", bits, i, got, want)
+				}
+			} else if got != 0 {
+				wrong++
+			}
+		}
+		rate := float64(wrong) / float64(n-len(exceptions))
+		want := 1 / float64(uint64(1)<<bits)
+		if rate > want*1.2 {
+			t.Fatalf("fingerprint %d: false default rate %v want at most %v", bits, rate, want)
+		}
+		if len(f)*10 > len(full) {
+			t.Fatalf("fingerprint %d: sparse map takes %d bytes, full map %d bytes", bits, len(f), len(full))
+		}
+	}
+
+	flags := NewSparse(true, map[string]bool{"off": false}, 0, 16)
+	if !GetSparse[string, bool](flags, "on") || GetSparse[string, bool](flags, "off") {
+		t.Fatalf("bool sparse map returned wrong values")
+	}
+	for i := 0; i < 100; i++ {
+		if !GetSparse[string, bool](flags, fmt.Sprint("key", i)) {
+			t.Fatalf("default key %d returned false", i)
+		}
+	}
+	if got := GetSparse[int, int8](NewSparse(int8(-1), map[int]int8{}, 0, 8), 5); got != -1 {
+		t.Fatalf("empty sparse map returned %d want -1", got)
+	}
+}
+
+func TestSparseDefaults(t *testing.T) {
+	exceptions := map[int]int16{1: 5, 2: -3, 3: 7, 4: 5, 5: 9}
+	f := NewSparse(int16(5), exceptions, 8, 32)
+
+	// exceptions equal to the default aren't stored
+	_, inner := unwrap(f, kindSparse)
+	for _, k := range []int{1, 4} {
+		if _, ok := GetVerified(inner, 8, k); ok {
+			t.Fatalf("exception %d equal to the default was stored", k)
+		}
+	}
+	for k, v := range exceptions {
+		if got := GetSparse[int, int16](f, k); got != v {
+			t.Fatalf("exception %d = %d want %d", k, got, v)
+		}
+	}
+	// keys which aren't exceptions return the default
+	for i := 6; i < 10000; i++ {
+		if got := GetSparse[int, int16](f, i); got != 5 {
+			t.Fatalf("key %d = %d want the default 5", i, got)
+		}
+	}
+}
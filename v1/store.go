@@ -1,11 +1,11 @@
 package v1
 
 import "encoding/binary"
-import sha256 "github.com/minio/sha256-simd"
 
 const ROUNDS = 8
 
-func store(fs []byte, data []byte, answer []byte, bitLimit byte) uint64 {
+// store writes answer for the key hashed to datb
+func store(fs []byte, datb *[32]byte, answer []byte, bitLimit byte) uint64 {
 	if len(fs) == 0 {
 		return 0
 	}
@@ -13,9 +13,6 @@ func store(fs []byte, data []byte, answer []byte, bitLimit byte) uint64 {
 		return 0
 	}
 
-	var datb [32]byte
-	datb = sha256.Sum256(data)
-
 	baseSize := uint64(len(fs))
 	cells := cellSize(baseSize - 2)
 	storedBits := uint64(bitLimit)
@@ -119,7 +116,7 @@ outer:
 }
 
 // bloom put
-func put(fs []byte, data []byte, funcs byte) (ret byte) {
+func put(fs []byte, datb *[32]byte, funcs byte) (ret byte) {
 	if len(fs) == 0 {
 		return 0
 	}
@@ -127,9 +124,6 @@ func put(fs []byte, data []byte, funcs byte) (ret byte) {
 		return 0
 	}
 
-	var datb [32]byte
-	datb = sha256.Sum256(data)
-
 	baseSize := uint64(len(fs))
 	cells := bitSize(baseSize - 2)
 
@@ -0,0 +1,99 @@
+package v1
+
+import (
+	"encoding/binary"
+	"math/bits"
+
+	sha256 "github.com/minio/sha256-simd"
+)
+
+// NewVarLen generates the map based on map m holding values of differing lengths.
+// The length of every value is stored in a bit limited length filter, the value bytes in
+// an Unlimited filter, so that GetBytesValue and GetStringValue return exactly the stored value.
+// The bloom stage, if any, is applied to the length filter only.
+func NewVarLen[K comparable, V string | []byte](m map[K]V, bloomFuncs byte) []byte {
+	var maxLen uint64
+	lengths := make(map[K]uint64, len(m))
+	for k, v := range m {
+		lengths[k] = uint64(len(v))
+		if maxLen < uint64(len(v)) {
+			maxLen = uint64(len(v))
+		}
+	}
+	lenBits := byte(bits.Len64(maxLen))
+	if lenBits == 0 {
+		lenBits = 1
+	}
+	lengthCore := New(lengths, lenBits, bloomFuncs)
+	dataCore := New(m, Unlimited, 0)
+
+	meta := make([]byte, 2*binary.MaxVarintLen64)
+	l := binary.PutUvarint(meta, maxLen)
+	l += binary.PutUvarint(meta[l:], uint64(len(lengthCore)))
+	return wrap(kindVarLen, meta[:l], append(lengthCore, dataCore...))
+}
+
+// varLenCores splits a filter created by NewVarLen into the length and the data filters
+func varLenCores(f []byte) (maxLen uint64, lengthCore, dataCore []byte) {
+	meta, core := unwrap(f, kindVarLen)
+	maxLen, l := binary.Uvarint(meta)
+	if l <= 0 {
+		panic("filter metadata truncated")
+	}
+	n, m := binary.Uvarint(meta[l:])
+	if m <= 0 || n > uint64(len(core)) {
+		panic("filter metadata truncated")
+	}
+	return maxLen, core[:n], core[n:]
+}
+
+// getVarLen retrieves the value of the encoded key k from a filter created by NewVarLen,
+// the key is hashed once for both of the filters
+func getVarLen(f []byte, k []byte) []byte {
+	maxLen, lengthCore, dataCore := varLenCores(f)
+	if len(lengthCore) <= 2 {
+		return nil
+	}
+	datb := sha256.Sum256(k)
+	lenBits := lengthCore[len(lengthCore)-1]
+	var buf [8]byte
+	b := getHashed(lengthCore, &datb, uint64(lenBits), lengthCore[len(lengthCore)-2])
+	if b == nil {
+		return nil
+	}
+	copy(buf[8-len(b):], b)
+	n := binary.BigEndian.Uint64(buf[:])
+	if n > maxLen || (n > 0 && len(dataCore) <= 2) {
+		// lengths above maxLen are never stored, the key is surely absent
+		return nil
+	}
+	if n == 0 {
+		return []byte{}
+	}
+	return getHashed(dataCore, &datb, bitSize(n), 0)
+}
+
+// getValue retrieves the whole value of the encoded key k from a filter created by
+// NewVarLen, NewHeap or NewDict
+func getValue(f []byte, k []byte) []byte {
+	if _, _, ok := dictOf(f); ok {
+		return getDict(f, k)
+	}
+	if _, _, ok := unwrapOK(f, kindHeap); ok {
+		return getHeap(f, k)
+	}
+	return getVarLen(f, k)
+}
+
+// GetBytesValue retrieves the whole value based on comparable key from a filter created by
+// NewVarLen, NewHeap or NewDict. Values of NewHeap and NewDict filters are slices of the filter,
+// don't modify them.
+func GetBytesValue[K comparable](f []byte, key K) []byte {
+	return getValue(f, comparableToBytes(key))
+}
+
+// GetStringValue retrieves the whole value based on comparable key from a filter created by
+// NewVarLen, NewHeap or NewDict
+func GetStringValue[K comparable](f []byte, key K) string {
+	return string(getValue(f, comparableToBytes(key)))
+}
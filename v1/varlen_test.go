@@ -0,0 +1,43 @@
+package v1
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestVarLen(t *testing.T) {
+	m := make(map[int]string)
+	for i := 0; i < 1000; i++ {
+		m[i] = strings.Repeat(fmt.Sprint(i), i%13)
+	}
+	m[1000] = strings.Repeat("x", 300)
+	f := NewVarLen(m, 0)
+	for k, v := range m {
+		if got := GetStringValue(f, k); got != v {
+			t.Fatalf("GetStringValue(%d) = %q want %q", k, got, v)
+		}
+	}
+
+	b := NewVarLen(map[string][]byte{"a": {1, 2, 3}, "b": {}, "c": {0}}, 4)
+	for k, v := range map[string][]byte{"a": {1, 2, 3}, "b": nil, "c": {0}} {
+		if got := GetBytesValue(b, k); !bytes.Equal(got, v) {
+			t.Fatalf("GetBytesValue(%s) = %v want %v", k, got, v)
+		}
+	}
+	var absent int
+	for i := 0; i < 1000; i++ {
+		if GetBytesValue(b, fmt.Sprint("absent", i)) == nil {
+			absent++
+		}
+	}
+	if absent < 900 {
+		t.Fatalf("bloom stage detected only %d of 1000 absent keys", absent)
+	}
+
+	empty := NewVarLen(map[int]string{}, 0)
+	if got := GetStringValue(empty, 1); got != "" {
+		t.Fatalf("empty filter returned %q", got)
+	}
+}
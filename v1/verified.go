@@ -0,0 +1,144 @@
+package v1
+
+// mix64 is the splitmix64 finalizer
+func mix64(h uint64) uint64 {
+	h ^= h >> 30
+	h *= 0xbf58476d1ce4e5b9
+	h ^= h >> 27
+	h *= 0x94d049bb133111eb
+	h ^= h >> 31
+	return h
+}
+
+// fingerprint derives a key fingerprint of the given bit size, independent of the
+// sha256 digest used to place the key in the cells
+func fingerprint(data []byte, bits byte) uint64 {
+	// FNV-1a
+	h := uint64(14695981039346656037)
+	for _, c := range data {
+		h ^= uint64(c)
+		h *= 1099511628211
+	}
+	// FNV low bits depend on the low bits of the input only
+	h = mix64(h)
+	if bits < 64 {
+		h &= 1<<bits - 1
+	}
+	return h
+}
+
+// bitAt returns bit i of a big-endian value, counting from the least significant bit
+func bitAt(value []byte, i uint64) byte {
+	if i >= uint64(len(value))*8 {
+		return 0
+	}
+	return (value[uint64(len(value))-(i>>3)-1] >> (i & 7)) & 1
+}
+
+// setBit sets bit i of a big-endian value, counting from the least significant bit
+func setBit(value []byte, i uint64) {
+	value[uint64(len(value))-(i>>3)-1] |= 1 << (i & 7)
+}
+
+// NewVerified generates the map based on map m, storing a fingerprintBits wide key
+// fingerprint in the least significant bits of every value.
+//
+// GetVerified then reports absent keys as missing except with rate 2^-fingerprintBits,
+// which is usually smaller than the bloom stage for the same rate.
+// The fingerprint can be combined with bloomFuncs.
+func NewVerified[K comparable, V Value](m map[K]V, bitLimit, bloomFuncs, fingerprintBits byte) []byte {
+	if fingerprintBits > 64 {
+		panic("fingerprint can't be wider than 64 bits")
+	}
+	pairs := materialize(m, &bitLimit)
+
+	var storedLimit = bitLimit
+	if bitLimit != 0 {
+		if uint64(bitLimit)+uint64(fingerprintBits) > 255 {
+			panic("bit limit with fingerprint exceeds 255 bits")
+		}
+		storedLimit = bitLimit + fingerprintBits
+	}
+
+	for i := range pairs {
+		value := *kvPairValue(&pairs[i])
+		valueBits := uint64(len(value)) * 8
+		if bitLimit != 0 {
+			if len(value) != int(bitLimit+7)/8 {
+				panic("inserting value exceeding bit limit when bit limit set")
+			}
+			valueBits = uint64(bitLimit)
+		}
+		fp := fingerprint(*kvPairKey(&pairs[i]), fingerprintBits)
+		stored := make([]byte, (valueBits+uint64(fingerprintBits)+7)/8)
+		for j := uint64(0); j < uint64(fingerprintBits); j++ {
+			if (fp>>j)&1 == 1 {
+				setBit(stored, j)
+			}
+		}
+		for j := uint64(0); j < valueBits; j++ {
+			if bitAt(value, j) == 1 {
+				setBit(stored, j+uint64(fingerprintBits))
+			}
+		}
+		*kvPairValue(&pairs[i]) = stored
+	}
+
+	var core []byte
+	if len(pairs) == 0 {
+		core = []byte{bloomFuncs, storedLimit}
+	} else {
+		core = create(pairs.iter, storedLimit, bloomFuncs)
+	}
+	return wrap(kindVerified, []byte{fingerprintBits}, core)
+}
+
+// GetVerified retrieves an item based on comparable key and value bit size from a filter
+// created by NewVerified, ok is false if the key wasn't inserted
+func GetVerified[K comparable](f []byte, valBitSize uint64, key K) (value []byte, ok bool) {
+	meta, core := unwrap(f, kindVerified)
+	fingerprintBits := meta[0]
+	if len(core) <= 2 {
+		return nil, false
+	}
+	k := comparableToBytes(key)
+	anslen := valBitSize + uint64(fingerprintBits)
+	if core[len(core)-1] == Unlimited {
+		// unlimited values are stored whole bytes at a time
+		anslen = bitSize((anslen + 7) / 8)
+	}
+	data := get(core, k, anslen, core[len(core)-2])
+	if data == nil {
+		return nil, false
+	}
+	fp := fingerprint(k, fingerprintBits)
+	for j := uint64(0); j < uint64(fingerprintBits); j++ {
+		if uint64(bitAt(data, j)) != (fp>>j)&1 {
+			return nil, false
+		}
+	}
+	value = make([]byte, (valBitSize+7)/8)
+	for j := uint64(0); j < valBitSize; j++ {
+		if bitAt(data, j+uint64(fingerprintBits)) == 1 {
+			setBit(value, j)
+		}
+	}
+	return value, true
+}
+
+// GetBoolVerified retrieves a bool based on comparable key from a filter created by NewVerified,
+// ok is false if the key wasn't inserted
+func GetBoolVerified[K comparable](f []byte, key K) (value bool, ok bool) {
+	data, ok := GetVerified(f, 1, key)
+	return ok && data[0] == 1, ok
+}
+
+// GetNumVerified retrieves a number based on comparable key and value bit size from a filter
+// created by NewVerified, ok is false if the key wasn't inserted
+func GetNumVerified[K comparable](f []byte, valBitSize uint64, key K) (value uint64, ok bool) {
+	data, ok := GetVerified(f, valBitSize, key)
+	for _, b := range data {
+		value = value<<8 | uint64(b)
+	}
+	return value, ok
+}
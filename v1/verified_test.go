@@ -0,0 +1,59 @@
+package v1
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestVerifiedFalsePositiveRate(t *testing.T) {
+	const n = 2000
+	const heldOut = 100000
+
+	m := make(map[string]uint16)
+	for i := 0; i < n; i++ {
+		m[fmt.Sprint("key", i)] = uint16(i * 31)
+	}
+	for _, bits := range []byte{1, 4, 8} {
+		f := NewVerified(m, 16, 0, bits)
+		for k, v := range m {
+			got, ok := GetNumVerified(f, 16, k)
+			if !ok || uint16(got) != v {
+				t.Fatalf("fingerprint %d: key %s returned %d, %v want %d, true", bits, k, got, ok, v)
+			}
+		}
+		var falsePositives int
+		for i := n; i < n+heldOut; i++ {
+			if _, ok := GetNumVerified(f, 16, fmt.Sprint("key", i)); ok {
+				falsePositives++
+			}
+		}
+		rate := float64(falsePositives) / heldOut
+		want := 1 / float64(uint64(1)<<bits)
+		if rate < want*0.8 || rate > want*1.2 {
+			t.Fatalf("fingerprint %d: rate %v want about %v", bits, rate, want)
+		}
+	}
+}
+
+func TestVerifiedValues(t *testing.T) {
+	b := NewVerified(map[int]bool{1: true, 2: false, 3: true}, 1, 0, 12)
+	for k, v := range map[int]bool{1: true, 2: false, 3: true} {
+		if got, ok := GetBoolVerified(b, k); got != v || !ok {
+			t.Fatalf("GetBoolVerified(%d) = %v, %v want %v, true", k, got, ok, v)
+		}
+	}
+
+	s := NewVerified(map[string]string{"a": "hello", "b": "hi"}, Unlimited, 3, 10)
+	if got, ok := GetVerified(s, 8*5, "a"); !ok || !bytes.Equal(got, []byte("hello")) {
+		t.Fatalf("GetVerified(a) = %q, %v", got, ok)
+	}
+	if got, ok := GetVerified(s, 8*2, "b"); !ok || !bytes.Equal(got, []byte("hi")) {
+		t.Fatalf("GetVerified(b) = %q, %v", got, ok)
+	}
+
+	e := NewVerified(map[string]bool{}, 1, 0, 8)
+	if _, ok := GetBoolVerified(e, "a"); ok {
+		t.Fatalf("empty verified filter reported a key present")
+	}
+}
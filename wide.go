@@ -0,0 +1,116 @@
+package quaternary
+
+// wordsOf returns the number of 64 bit words holding the given number of planes
+func wordsOf(planes int) int {
+	return (planes + 63) / 64
+}
+
+// groupOf returns the group of 64 bit planes of the Filters belonging to the 64 bit word w
+func (f Filters) groupOf(w int) Filters {
+	end := 64 * (w + 1)
+	if end > len(f) {
+		end = len(f)
+	}
+	return f[64*w : end]
+}
+
+// checkWide panics if a value of the map has more words than planes need
+func checkWide[K comparable](planes int, values map[K][]uint64) {
+	for _, v := range values {
+		if len(v) > wordsOf(planes) {
+			panic("value has more words than the planes of the wide filters")
+		}
+	}
+}
+
+func createWide[T Number](planes int, numbers map[T][]uint64, data map[[64]byte][]uint64) Filters {
+	if planes < 0 {
+		panic("negative number of planes")
+	}
+	checkWide(planes, numbers)
+	checkWide(planes, data)
+	ret := make(Filters, 0, planes)
+	for w := 0; w < wordsOf(planes); w++ {
+		bits := planes - 64*w
+		if bits > 64 {
+			bits = 64
+		}
+		var wordNumbers = make(map[T]uint64, len(numbers))
+		for k, v := range numbers {
+			if w < len(v) {
+				wordNumbers[k] = v[w]
+			} else {
+				wordNumbers[k] = 0
+			}
+		}
+		var wordData = make(map[[64]byte]uint64, len(data))
+		for k, v := range data {
+			if w < len(v) {
+				wordData[k] = v[w]
+			} else {
+				wordData[k] = 0
+			}
+		}
+		checkLabels(byte(bits), wordNumbers)
+		checkLabels(byte(bits), wordData)
+		ret = append(ret, multi(create64(byte(bits), wordNumbers, wordData))...)
+	}
+	return ret
+}
+
+// MakeWide creates new Filters holding values of any number of bit planes from a map of numeric values.
+// Values are little endian words, bit i is stored in word i/64, missing words are zero.
+// Every group of 64 planes is sized independently. It panics if a value has bits above planes.
+func MakeWide[T Number](planes int, numbers map[T][]uint64) Filters {
+	return createWide(planes, numbers, make(map[[64]byte][]uint64))
+}
+
+// MakeBytesWide creates new Filters holding values of any number of bit planes from a map of 64-byte arrays.
+func MakeBytesWide(planes int, data map[[64]byte][]uint64) Filters {
+	return createWide(planes, make(map[int][]uint64), data)
+}
+
+// MakeStringWide creates new Filters holding values of any number of bit planes from a map of strings.
+func MakeStringWide(planes int, string_map map[string][]uint64) Filters {
+	var data = make(map[[64]byte][]uint64)
+	var nums = make(map[uint64][]uint64)
+	for k, v := range string_map {
+		if len(k) <= 7 {
+			nums[stringToUint64(k)] = v
+		} else {
+			data[stringsToByte64(k)] = v
+		}
+	}
+	return createWide(planes, nums, data)
+}
+
+// GetIntWide retrieves the value of an int key from Filters created by MakeWide.
+func (f Filters) GetIntWide(num int) []uint64 {
+	return f.GetUint64Wide(uint64(num))
+}
+
+// GetUint64Wide retrieves the value of an uint64 key from Filters created by MakeWide.
+func (f Filters) GetUint64Wide(num uint64) []uint64 {
+	ret := make([]uint64, wordsOf(len(f)))
+	for w := range ret {
+		ret[w] = f.groupOf(w).GetUint64Multi(num)
+	}
+	return ret
+}
+
+// GetBytesWide retrieves the value of a 64-byte array key from Filters created by MakeBytesWide.
+func (f Filters) GetBytesWide(data [64]byte) []uint64 {
+	ret := make([]uint64, wordsOf(len(f)))
+	for w := range ret {
+		ret[w] = f.groupOf(w).GetBytesMulti(data)
+	}
+	return ret
+}
+
+// GetStringWide retrieves the value of a string key from Filters created by MakeStringWide.
+func (f Filters) GetStringWide(str string) []uint64 {
+	if len(str) <= 7 {
+		return f.GetUint64Wide(stringToUint64(str))
+	}
+	return f.GetBytesWide(stringsToByte64(str))
+}
@@ -0,0 +1,84 @@
+package quaternary
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestMakeWide(t *testing.T) {
+	const test = 1000
+	rnd := rand.New(rand.NewSource(1))
+
+	for _, planes := range []int{1, 64, 128, 200, 256} {
+		var nums = make(map[int][]uint64)
+		var strs = make(map[string][]uint64)
+		var mask = make([]uint64, wordsOf(planes))
+		for w := range mask {
+			mask[w] = ^uint64(0)
+			if rest := planes - 64*w; rest < 64 {
+				mask[w] = 1<<uint(rest) - 1
+			}
+		}
+		for i := 0; i < test; i++ {
+			v := make([]uint64, len(mask))
+			s := make([]uint64, len(mask))
+			for w := range v {
+				v[w] = rnd.Uint64() & mask[w]
+				s[w] = rnd.Uint64() & mask[w]
+			}
+			nums[i] = v
+			strs[fmt.Sprint("wide key ", i)] = s
+		}
+		fn := MakeWide(planes, nums)
+		fs := MakeStringWide(planes, strs)
+		if len(fn) != planes || len(fs) != planes {
+			t.Fatalf("%d planes: got %d and %d filters", planes, len(fn), len(fs))
+		}
+		for k, v := range nums {
+			if got := fn.GetIntWide(k); !reflect.DeepEqual(got, v) {
+				t.Fatalf("%d planes: GetIntWide(%d) = %x want %x", planes, k, got, v)
+			}
+		}
+		for k, v := range strs {
+			if got := fs.GetStringWide(k); !reflect.DeepEqual(got, v) {
+				t.Fatalf("%d planes: GetStringWide(%q) = %x want %x", planes, k, got, v)
+			}
+		}
+	}
+}
+
+func TestMakeWideShortValues(t *testing.T) {
+	var m = map[[64]byte][]uint64{
+		stringsToByte64("a"): {1},
+		stringsToByte64("b"): {2, 3},
+		stringsToByte64("c"): nil,
+	}
+	f := MakeBytesWide(128, m)
+	for k, v := range m {
+		want := make([]uint64, 2)
+		copy(want, v)
+		if got := f.GetBytesWide(k); !reflect.DeepEqual(got, want) {
+			t.Fatalf("GetBytesWide = %x want %x", got, want)
+		}
+	}
+}
+
+func TestMakeWideRejectsWideValues(t *testing.T) {
+	for name, build := range map[string]func(){
+		"bits":   func() { MakeWide(70, map[int][]uint64{1: {3, 1 << 6}}) },
+		"words":  func() { MakeWide(64, map[int][]uint64{1: {3, 1}}) },
+		"bytes":  func() { MakeBytesWide(3, map[[64]byte][]uint64{{1}: {8}}) },
+		"string": func() { MakeStringWide(65, map[string][]uint64{"a long key": {0, 2}}) },
+	} {
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Fatalf("%s: expected panic for a value wider than planes", name)
+				}
+			}()
+			build()
+		}()
+	}
+}